@@ -10,6 +10,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/lbryio/herald.go/db/prefixes"
 	"github.com/lbryio/herald.go/db/stack"
 	"github.com/lbryio/herald.go/internal"
@@ -39,6 +40,9 @@ const (
 	MaxTakeoverDelay                  = 4032
 	// Initial size constants
 	InitialTxCountSize = 1200000
+	// ReorgLimit is the number of recent blocks whose changes are kept
+	// in memory, so a reorg can undo them.
+	ReorgLimit = 200
 )
 
 //
@@ -61,6 +65,14 @@ type ReadOnlyDBColumnFamily struct {
 	FilteredChannels       map[string][]byte
 	Grp                    *stop.Group
 	Cleanup                func()
+	Trending               *TrendingScores
+	ClaimValueCache        *ttlcache.Cache
+	ClaimSignatureCache    *ttlcache.Cache
+	ChannelCache           *ttlcache.Cache
+	ChannelStatsCache      *ttlcache.Cache
+	AddressClaimsCache     *ttlcache.Cache
+	TopStaked              *TopStakedCache
+	Stats                  *StatsCache
 }
 
 type ResolveResult struct {
@@ -92,6 +104,8 @@ type ResolveResult struct {
 	ChannelTxHash      []byte
 	ChannelTxPostition uint16
 	ChannelHeight      uint32
+	TrendingScore      float64
+	ClaimValue         *pb.Claim
 }
 
 type ResolveError struct {
@@ -264,6 +278,8 @@ func (res *ResolveResult) ToOutput() *pb.Output {
 		ClaimsInChannel:  res.ClaimsInChannel,
 		EffectiveAmount:  res.EffectiveAmount,
 		SupportAmount:    res.SupportAmount,
+		TrendingScore:    res.TrendingScore,
+		Value:            res.ClaimValue,
 	}
 
 	claim := &pb.Output_Claim{
@@ -332,6 +348,9 @@ func interruptRequested(interrupted <-chan struct{}) bool {
 	return false
 }
 
+// IterCF iterates the rows selected by opts. Callers which stop reading
+// before the channel is closed must stop opts.Grp, which releases the
+// iterator.
 func IterCF(db *grocksdb.DB, opts *IterOptions) <-chan *prefixes.PrefixRowKV {
 	ch := make(chan *prefixes.PrefixRowKV)
 
@@ -362,6 +381,21 @@ func IterCF(db *grocksdb.DB, opts *IterOptions) <-chan *prefixes.PrefixRowKV {
 			}
 		}()
 
+		// A nil done channel never fires, so without a group sends block
+		// until received.
+		var done stop.Chan
+		if opts.Grp != nil {
+			done = opts.Grp.Ch()
+		}
+		send := func(kv *prefixes.PrefixRowKV) bool {
+			select {
+			case ch <- kv:
+				return true
+			case <-done:
+				return false
+			}
+		}
+
 		var prevKey []byte
 		// FIXME: There's messy uses of kv being nil / not nil here.
 		var kv *prefixes.PrefixRowKV = nil
@@ -371,13 +405,17 @@ func IterCF(db *grocksdb.DB, opts *IterOptions) <-chan *prefixes.PrefixRowKV {
 		}
 
 		if !it.Valid() && opts.IncludeStop && kv != nil {
-			ch <- kv
+			if !send(kv) {
+				return
+			}
 		}
 
 		kv = &prefixes.PrefixRowKV{}
 		for ; kv != nil && !opts.StopIteration(prevKey) && it.Valid(); it.Next() {
 			if kv = opts.ReadRow(&prevKey); kv != nil {
-				ch <- kv
+				if !send(kv) {
+					return
+				}
 			}
 			if opts.Grp != nil && interruptRequested(opts.Grp.Ch()) {
 				return
@@ -589,18 +627,24 @@ func GetDBColumnFamilies(name string, secondayPath string, cfNames []string, grp
 	}
 
 	myDB := &ReadOnlyDBColumnFamily{
-		DB:               db,
-		Handles:          handlesMap,
-		Opts:             roOpts,
-		BlockedStreams:   make(map[string][]byte),
-		BlockedChannels:  make(map[string][]byte),
-		FilteredStreams:  make(map[string][]byte),
-		FilteredChannels: make(map[string][]byte),
-		TxCounts:         nil,
-		LastState:        nil,
-		Height:           0,
-		Headers:          nil,
-		Grp:              grp,
+		DB:                  db,
+		Handles:             handlesMap,
+		Opts:                roOpts,
+		BlockedStreams:      make(map[string][]byte),
+		BlockedChannels:     make(map[string][]byte),
+		FilteredStreams:     make(map[string][]byte),
+		FilteredChannels:    make(map[string][]byte),
+		TxCounts:            nil,
+		LastState:           nil,
+		Height:              0,
+		Headers:             nil,
+		Grp:                 grp,
+		ClaimValueCache:     NewClaimValueCache(),
+		ClaimSignatureCache: NewClaimSignatureCache(),
+		ChannelCache:        NewChannelCache(),
+		ChannelStatsCache:   NewChannelStatsCache(),
+		AddressClaimsCache:  NewAddressClaimsCache(),
+		TopStaked:           NewTopStakedCache(),
 	}
 
 	err = myDB.ReadDBState() //TODO: Figure out right place for this
@@ -618,6 +662,21 @@ func GetDBColumnFamilies(name string, secondayPath string, cfNames []string, grp
 		return nil, err
 	}
 
+	err = myDB.InitTrending(myDB.LastState.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	err = myDB.InitStats(myDB.LastState.Height)
+	if err != nil {
+		return nil, err
+	}
+
+	err = myDB.InitTopStaked(myDB.LastState.Height)
+	if err != nil {
+		return nil, err
+	}
+
 	err = myDB.GetBlocksAndFilters()
 	if err != nil {
 		return nil, err
@@ -659,10 +718,31 @@ func (db *ReadOnlyDBColumnFamily) Advance(height uint32) {
 
 	db.TxCounts.Push(txCount)
 	db.Headers.Push(headerObj)
+
+	if err := db.advanceTrending(height); err != nil {
+		log.Error("updating trending:", err)
+	}
+
+	undo, err := db.blockUndo(height)
+	if err != nil {
+		log.Error("getting undo ops:", err)
+	}
+
+	if err := db.advanceStats(height, undo); err != nil {
+		log.Error("updating stats:", err)
+	}
+
+	if err := db.advanceTopStaked(height, undo); err != nil {
+		log.Error("updating top staked claims:", err)
+	}
 }
 
 // Unwind unwinds the db one block height
 func (db *ReadOnlyDBColumnFamily) Unwind() {
+	height := db.TxCounts.Len() - 1
+	db.unwindTrending(height)
+	db.unwindStats(height)
+	db.unwindTopStaked()
 	db.TxCounts.Pop()
 	db.Headers.Pop()
 }
@@ -670,6 +750,21 @@ func (db *ReadOnlyDBColumnFamily) Unwind() {
 // Shutdown shuts down the db.
 func (db *ReadOnlyDBColumnFamily) Shutdown() {
 	db.Grp.StopAndWait()
+	if db.ClaimValueCache != nil {
+		db.ClaimValueCache.Close()
+	}
+	if db.ClaimSignatureCache != nil {
+		db.ClaimSignatureCache.Close()
+	}
+	if db.ChannelCache != nil {
+		db.ChannelCache.Close()
+	}
+	if db.ChannelStatsCache != nil {
+		db.ChannelStatsCache.Close()
+	}
+	if db.AddressClaimsCache != nil {
+		db.AddressClaimsCache.Close()
+	}
 	log.Println("Calling cleanup...")
 	db.Cleanup()
 	log.Println("Leaving Shutdown...")
@@ -754,6 +849,13 @@ func (db *ReadOnlyDBColumnFamily) detectChanges(notifCh chan<- interface{}) erro
 	}
 	if rewound {
 		metrics.ReorgCount.Inc()
+		// The spikes of blocks disconnected past ReorgLimit are dropped
+		// by recomputing the scores.
+		if db.Trending.Incomplete() {
+			if err := db.InitTrending(lastHeight); err != nil {
+				return err
+			}
+		}
 		hash, err := db.GetBlockHash(lastHeight)
 		if err != nil {
 			return err
@@ -777,6 +879,18 @@ func (db *ReadOnlyDBColumnFamily) detectChanges(notifCh chan<- interface{}) erro
 			}
 			notifCh <- &internal.HeightHash{Height: uint64(height), BlockHash: hash}
 		}
+		// The stats are counted again if a block had no undo ops, or a
+		// disconnected block was older than the kept changes.
+		if db.Stats.Stale() {
+			if err := db.InitStats(state.Height); err != nil {
+				return err
+			}
+		}
+		// Ranking scans every claim, so it is done in the background, and
+		// only when the blocks couldn't be applied to the ranking.
+		if db.TopStaked.Stale() {
+			db.rankTopStakedAsync(state.Height)
+		}
 		//TODO: ClearCache
 		log.Warn("implement cache clearing")
 
@@ -0,0 +1,418 @@
+package db
+
+// db_claim.go contains functions for decoding the claims stored in txs.
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ReneKroon/ttlcache/v2"
+	"github.com/lbryio/herald.go/db/prefixes"
+	"github.com/lbryio/herald.go/internal"
+	pb "github.com/lbryio/herald.go/protobuf/go"
+	"github.com/lbryio/lbcd/btcec"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
+	"github.com/lbryio/lbcutil"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// ClaimValueCacheSize is the number of decoded claims kept in memory.
+	ClaimValueCacheSize = 10000
+	// ClaimSignatureCacheSize is the number of verified claim signatures
+	// kept in memory.
+	ClaimSignatureCacheSize = 10000
+
+	unsignedClaimVersion = 0
+	signedClaimVersion   = 1
+	claimSignatureLen    = 64
+)
+
+// ClaimValue is a decoded claim. SigningChannelHash and Signature are
+// only set for claims signed by a channel. SigningChannelHash is in the
+// byte order of the value, the reverse of the channel's claim hash.
+type ClaimValue struct {
+	Claim              *pb.Claim
+	SigningChannelHash []byte
+	Signature          []byte
+	// Payload is the serialized claim protobuf.
+	Payload []byte
+}
+
+// Claim types, named as in search.
+const (
+	ClaimTypeStream     = "stream"
+	ClaimTypeChannel    = "channel"
+	ClaimTypeRepost     = "repost"
+	ClaimTypeCollection = "collection"
+)
+
+// ClaimType returns the type of a claim, or "" if it has none.
+func ClaimType(claim *pb.Claim) string {
+	switch claim.GetType().(type) {
+	case *pb.Claim_Stream:
+		return ClaimTypeStream
+	case *pb.Claim_Channel:
+		return ClaimTypeChannel
+	case *pb.Claim_Repost:
+		return ClaimTypeRepost
+	case *pb.Claim_Collection:
+		return ClaimTypeCollection
+	}
+	return ""
+}
+
+// ParseClaimValue decodes the value of a claim script. Values start with a
+// version byte. Signed values follow it with the signing channel's claim
+// hash and the signature. Legacy, pre-protobuf values are not supported.
+func ParseClaimValue(value []byte) (*ClaimValue, error) {
+	if len(value) == 0 {
+		return nil, errors.New("empty claim value")
+	}
+	res := &ClaimValue{}
+	switch value[0] {
+	case unsignedClaimVersion:
+		res.Payload = value[1:]
+	case signedClaimVersion:
+		if len(value) < 1+20+claimSignatureLen {
+			return nil, errors.New("signed claim value too short")
+		}
+		res.SigningChannelHash = value[1 : 1+20]
+		res.Signature = value[1+20 : 1+20+claimSignatureLen]
+		res.Payload = value[1+20+claimSignatureLen:]
+	default:
+		return nil, fmt.Errorf("unsupported claim value version %d", value[0])
+	}
+	res.Claim = &pb.Claim{}
+	if err := proto.Unmarshal(res.Payload, res.Claim); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetTxByNum returns the confirmed tx with the given tx number.
+func (db *ReadOnlyDBColumnFamily) GetTxByNum(txNum uint32) (*wire.MsgTx, error) {
+	rawTxHash, err := db.GetTxHash(txNum)
+	if err != nil {
+		return nil, err
+	} else if rawTxHash == nil {
+		return nil, fmt.Errorf("tx %d not found", txNum)
+	}
+	txHash, err := chainhash.NewHash(rawTxHash)
+	if err != nil {
+		return nil, err
+	}
+	rawTx, err := db.GetTx(txHash)
+	if err != nil {
+		return nil, err
+	} else if rawTx == nil {
+		return nil, fmt.Errorf("tx %s not found", txHash)
+	}
+	tx := &wire.MsgTx{}
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// GetClaimScript returns the claim script of the given output.
+func (db *ReadOnlyDBColumnFamily) GetClaimScript(txNum uint32, position uint16) (*txscript.ClaimScript, error) {
+	tx, err := db.GetTxByNum(txNum)
+	if err != nil {
+		return nil, err
+	}
+	if int(position) >= len(tx.TxOut) {
+		return nil, fmt.Errorf("tx %d has no output %d", txNum, position)
+	}
+	return txscript.ExtractClaimScript(tx.TxOut[position].PkScript)
+}
+
+// ChannelClaimHash returns the claim hash of the signing channel, or nil if
+// the claim is unsigned.
+func (v *ClaimValue) ChannelClaimHash() []byte {
+	if v.SigningChannelHash == nil {
+		return nil
+	}
+	channelHash := make([]byte, len(v.SigningChannelHash))
+	copy(channelHash, v.SigningChannelHash)
+	internal.ReverseBytesInPlace(channelHash)
+	return channelHash
+}
+
+// SignatureDigest returns the digest signed by the channel, the sha256 of
+// the outpoint spent by the first input of the claim's tx (its tx hash and
+// little endian output index), the signing channel hash and the claim
+// payload.
+func (v *ClaimValue) SignatureDigest(firstInput *wire.OutPoint) []byte {
+	var index [4]byte
+	binary.LittleEndian.PutUint32(index[:], firstInput.Index)
+	digest := sha256.New()
+	digest.Write(firstInput.Hash[:])
+	digest.Write(index[:])
+	digest.Write(v.SigningChannelHash)
+	digest.Write(v.Payload)
+	return digest.Sum(nil)
+}
+
+// VerifySignature reports whether the claim was signed by the channel with
+// the given public key.
+func (v *ClaimValue) VerifySignature(firstInput *wire.OutPoint, publicKey *btcec.PublicKey) bool {
+	if len(v.Signature) != claimSignatureLen || publicKey == nil {
+		return false
+	}
+	signature := &btcec.Signature{
+		R: new(big.Int).SetBytes(v.Signature[:claimSignatureLen/2]),
+		S: new(big.Int).SetBytes(v.Signature[claimSignatureLen/2:]),
+	}
+	return signature.Verify(v.SignatureDigest(firstInput), publicKey)
+}
+
+// ParseChannelPublicKey decodes the public key of a channel, a DER encoded
+// SubjectPublicKeyInfo of a secp256k1 key.
+func ParseChannelPublicKey(der []byte) (*btcec.PublicKey, error) {
+	var info struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	rest, err := asn1.Unmarshal(der, &info)
+	if err != nil {
+		return nil, err
+	} else if len(rest) > 0 {
+		return nil, errors.New("trailing data after channel public key")
+	}
+	return btcec.ParsePubKey(info.PublicKey.Bytes, btcec.S256())
+}
+
+// ClaimSignature is the result of verifying the signature of a claim.
+type ClaimSignature struct {
+	// ChannelHash is the claim hash of the signing channel.
+	ChannelHash []byte
+	Valid       bool
+}
+
+// cachedClaimSignature is the signature of a claim at an output, verified
+// against the channel at an output.
+type cachedClaimSignature struct {
+	txNum           uint32
+	position        uint16
+	channelTxNum    uint32
+	channelPosition uint16
+	signature       *ClaimSignature
+}
+
+// NewClaimSignatureCache returns the cache used by GetClaimSignature.
+func NewClaimSignatureCache() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetCacheSizeLimit(ClaimSignatureCacheSize)
+	cache.SkipTTLExtensionOnHit(true)
+	return cache
+}
+
+// GetClaimSignature verifies the signature of the claim at the given
+// output against the current public key of its signing channel. It returns
+// nil for unsigned claims. Signatures of abandoned channels, or of claims
+// which aren't channels, are invalid. Results are cached per claim until
+// the claim or its channel is updated.
+func (db *ReadOnlyDBColumnFamily) GetClaimSignature(claimHash []byte, txNum uint32, position uint16) (*ClaimSignature, error) {
+	value, err := db.GetClaimValue(claimHash, txNum, position)
+	if err != nil {
+		return nil, err
+	} else if value.SigningChannelHash == nil {
+		return nil, nil
+	}
+	res := &ClaimSignature{ChannelHash: value.ChannelClaimHash()}
+
+	channelTxo, err := db.GetClaimTxo(res.ChannelHash)
+	if err != nil {
+		return nil, err
+	} else if channelTxo == nil {
+		return res, nil
+	}
+	key := string(claimHash)
+	if db.ClaimSignatureCache != nil {
+		if cached, err := db.ClaimSignatureCache.Get(key); err == nil {
+			entry := cached.(*cachedClaimSignature)
+			if entry.txNum == txNum && entry.position == position &&
+				entry.channelTxNum == channelTxo.TxNum && entry.channelPosition == channelTxo.Position {
+				return entry.signature, nil
+			}
+		}
+	}
+
+	res.Valid, err = db.verifyClaimSignature(value, txNum, res.ChannelHash, channelTxo)
+	if err != nil {
+		return nil, err
+	}
+	if db.ClaimSignatureCache != nil {
+		entry := &cachedClaimSignature{
+			txNum:           txNum,
+			position:        position,
+			channelTxNum:    channelTxo.TxNum,
+			channelPosition: channelTxo.Position,
+			signature:       res,
+		}
+		if err := db.ClaimSignatureCache.Set(key, entry); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// verifyClaimSignature verifies the signature of a claim, in the tx
+// txNum, against the public key of the channel at channelTxo.
+func (db *ReadOnlyDBColumnFamily) verifyClaimSignature(value *ClaimValue, txNum uint32, channelHash []byte, channelTxo *prefixes.ClaimToTXOValue) (bool, error) {
+	channelValue, err := db.GetClaimValue(channelHash, channelTxo.TxNum, channelTxo.Position)
+	if err != nil {
+		return false, err
+	}
+	channel := channelValue.Claim.GetChannel()
+	if channel == nil {
+		return false, nil
+	}
+	publicKey, err := ParseChannelPublicKey(channel.PublicKey)
+	if err != nil {
+		return false, nil
+	}
+
+	tx, err := db.GetTxByNum(txNum)
+	if err != nil {
+		return false, err
+	} else if len(tx.TxIn) == 0 {
+		return false, nil
+	}
+	return value.VerifySignature(&tx.TxIn[0].PreviousOutPoint, publicKey), nil
+}
+
+// ClaimHashForOutpoint returns the hash of the claim created by the output
+// nout of the tx txHash. txHash is in the byte order of the tx.
+func ClaimHashForOutpoint(txHash []byte, nout uint32) []byte {
+	buf := make([]byte, len(txHash)+4)
+	copy(buf, txHash)
+	binary.BigEndian.PutUint32(buf[len(txHash):], nout)
+	claimHash := lbcutil.Hash160(buf)
+	internal.ReverseBytesInPlace(claimHash)
+	return claimHash
+}
+
+// OutpointClaim is the claim created, updated or supported by an outpoint.
+type OutpointClaim struct {
+	ClaimHash []byte
+	TxNum     uint32
+	Position  uint16
+	// Type is one of TXOTypeClaim, TXOTypeUpdate or TXOTypeSupport.
+	Type string
+	// Current is set if the outpoint is the claim's current TXO, or an
+	// unspent support.
+	Current bool
+}
+
+// GetOutpointClaim returns the claim of an outpoint. Current TXOs are found
+// in TXOToClaim and SupportToClaim, spent ones by decoding their script.
+// Returns nil if the tx is unconfirmed or the output isn't a claim or
+// support.
+func (db *ReadOnlyDBColumnFamily) GetOutpointClaim(txHash *chainhash.Hash, position uint16) (*OutpointClaim, error) {
+	txNum, err := db.GetTxNum(txHash)
+	if err != nil || txNum == nil {
+		return nil, err
+	}
+	res := &OutpointClaim{TxNum: txNum.TxNum, Position: position}
+
+	info, err := db.GetTXOClaimInfo(txNum.TxNum, position)
+	if err != nil {
+		return nil, err
+	}
+	if info != nil {
+		res.ClaimHash = info.ClaimHash
+		res.Type = info.Type
+		if info.Type == TXOTypeSupport {
+			res.Current = true
+		} else {
+			claimTxo, err := db.GetClaimTxo(info.ClaimHash)
+			if err != nil {
+				return nil, err
+			}
+			res.Current = claimTxo != nil && claimTxo.TxNum == txNum.TxNum && claimTxo.Position == position
+		}
+		return res, nil
+	}
+
+	tx, err := db.GetTxByNum(txNum.TxNum)
+	if err != nil {
+		return nil, err
+	} else if int(position) >= len(tx.TxOut) {
+		return nil, nil
+	}
+	script, err := txscript.ExtractClaimScript(tx.TxOut[position].PkScript)
+	if err != nil {
+		return nil, nil
+	}
+	switch script.Opcode {
+	case txscript.OP_CLAIMNAME:
+		res.ClaimHash = ClaimHashForOutpoint(txHash[:], uint32(position))
+		res.Type = TXOTypeClaim
+	case txscript.OP_UPDATECLAIM, txscript.OP_SUPPORTCLAIM:
+		res.ClaimHash = make([]byte, len(script.ClaimID))
+		copy(res.ClaimHash, script.ClaimID)
+		internal.ReverseBytesInPlace(res.ClaimHash)
+		res.Type = TXOTypeUpdate
+		if script.Opcode == txscript.OP_SUPPORTCLAIM {
+			res.Type = TXOTypeSupport
+		}
+	default:
+		return nil, nil
+	}
+	return res, nil
+}
+
+type cachedClaimValue struct {
+	txNum    uint32
+	position uint16
+	value    *ClaimValue
+}
+
+// GetClaimValue returns the decoded claim of claimHash at the given output.
+// Values are cached per claim until the claim is updated.
+func (db *ReadOnlyDBColumnFamily) GetClaimValue(claimHash []byte, txNum uint32, position uint16) (*ClaimValue, error) {
+	key := string(claimHash)
+	if db.ClaimValueCache != nil {
+		if cached, err := db.ClaimValueCache.Get(key); err == nil {
+			entry := cached.(*cachedClaimValue)
+			if entry.txNum == txNum && entry.position == position {
+				return entry.value, nil
+			}
+		}
+	}
+
+	script, err := db.GetClaimScript(txNum, position)
+	if err != nil {
+		return nil, err
+	}
+	value, err := ParseClaimValue(script.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	if db.ClaimValueCache != nil {
+		entry := &cachedClaimValue{txNum: txNum, position: position, value: value}
+		if err := db.ClaimValueCache.Set(key, entry); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// NewClaimValueCache returns the cache used by GetClaimValue.
+func NewClaimValueCache() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetCacheSizeLimit(ClaimValueCacheSize)
+	cache.SkipTTLExtensionOnHit(true)
+	return cache
+}
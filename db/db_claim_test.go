@@ -0,0 +1,142 @@
+package db_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	dbpkg "github.com/lbryio/herald.go/db"
+	pb "github.com/lbryio/herald.go/protobuf/go"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/claimtrie/change"
+	"github.com/lbryio/lbcd/wire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseClaimValue(t *testing.T) {
+	claim := &pb.Claim{
+		Type:  &pb.Claim_Stream{Stream: &pb.Stream{Author: "author"}},
+		Title: "title",
+	}
+	payload, err := proto.Marshal(claim)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unsigned := append([]byte{0}, payload...)
+	value, err := dbpkg.ParseClaimValue(unsigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.Claim.GetTitle() != "title" || value.Claim.GetStream().GetAuthor() != "author" {
+		t.Errorf("Unexpected claim %v", value.Claim)
+	}
+	if value.SigningChannelHash != nil || value.Signature != nil {
+		t.Error("Expected an unsigned claim")
+	}
+
+	channelHash := bytes.Repeat([]byte{1}, 20)
+	signature := bytes.Repeat([]byte{2}, 64)
+	signed := append([]byte{1}, channelHash...)
+	signed = append(signed, signature...)
+	signed = append(signed, payload...)
+	value, err = dbpkg.ParseClaimValue(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(value.SigningChannelHash, channelHash) || !bytes.Equal(value.Signature, signature) {
+		t.Error("Unexpected signing channel or signature")
+	}
+	if !bytes.Equal(value.Payload, payload) || value.Claim.GetTitle() != "title" {
+		t.Errorf("Unexpected claim %v", value.Claim)
+	}
+
+	for _, bad := range [][]byte{nil, {1, 2, 3}, {'{', '}'}} {
+		if _, err := dbpkg.ParseClaimValue(bad); err == nil {
+			t.Errorf("Expected an error for %x", bad)
+		}
+	}
+}
+
+// A signed mainnet claim and its channel, from lbry.go's
+// TestV2ValidateClaimSignature.
+const (
+	mainnetChannelHex = "00125a0a583056301006072a8648ce3d020106052b8104000a034200045a0343c155302280da01ae0001b7295241eb03c42a837acf92ccb9680892f7db50fd1d3c14b28bb594e304f05fc4ae7c1f222a85d1d1a3461b3cfb9906f66cb5"
+	mainnetClaimHex   = "015cb78e424a34fbf79b67f9107430427aa62373e69b4998a29ecec8f14a9e0a213a043ced8064c069d7e464b5fd3ccb92b45bd59b15c0e1bb27e3c366d43f86a9a6b5ad42647a1aad69a73ac50b19ae3ec978c2c70aa2010a99010a301c662f19abc461e7eddecf165adfa7fca569e209773f3db31241c1e297f0a8d5b3e4768828b065fbeb1d6776f61073f6121b3031202d20556e6d6173746572656420496d70756c7365732e377a187a22146170706c69636174696f6e2f782d6578742d377a32302eb61ea475017e28c013616a56c1219ba90dc35fffff453d9675146f648f66634e0d1516528d37aba9f5801229d9f2181a044e6f6e6542087465737420707562520062020801"
+	mainnetChannelId  = "e67323a67a42307410f9679bf7fb344a428eb75c"
+	mainnetFirstInput = "becb96a4a2e66bd24f083772fe9da904654ea9b5f07cc5bfbee233355911ddb1"
+)
+
+func TestVerifyClaimSignature(t *testing.T) {
+	rawChannel, _ := hex.DecodeString(mainnetChannelHex)
+	channel, err := dbpkg.ParseClaimValue(rawChannel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicKey, err := dbpkg.ParseChannelPublicKey(channel.Claim.GetChannel().GetPublicKey())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rawClaim, _ := hex.DecodeString(mainnetClaimHex)
+	value, err := dbpkg.ParseClaimValue(rawClaim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimHash := hex.EncodeToString(value.ChannelClaimHash()); claimHash != mainnetChannelId {
+		t.Errorf("Expected channel %s, got %s", mainnetChannelId, claimHash)
+	}
+
+	txHash, err := chainhash.NewHashFromStr(mainnetFirstInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	firstInput := wire.NewOutPoint(txHash, 0)
+	// sha256 of the input's tx hash, its little endian index, the channel
+	// hash and the payload.
+	want := "e5fd956b086765fc9143dcf3b81c94a66ec83d86e8fc02471a7618a8be0a0258"
+	if digest := hex.EncodeToString(value.SignatureDigest(firstInput)); digest != want {
+		t.Errorf("Expected digest %s, got %s", want, digest)
+	}
+	if !value.VerifySignature(firstInput, publicKey) {
+		t.Error("Expected a valid signature")
+	}
+	if value.VerifySignature(wire.NewOutPoint(txHash, 1), publicKey) {
+		t.Error("Expected an invalid signature for another output of the input's tx")
+	}
+	if value.VerifySignature(wire.NewOutPoint(&chainhash.Hash{}, 0), publicKey) {
+		t.Error("Expected an invalid signature for another input")
+	}
+
+	if _, err := dbpkg.ParseChannelPublicKey([]byte{1, 2, 3}); err == nil {
+		t.Error("Expected an error for an invalid public key")
+	}
+}
+
+func TestClaimHashForOutpoint(t *testing.T) {
+	txHash, err := chainhash.NewHashFromStr("7ea4a4ee2d9b0f0c27ef1e4d3d2da1a1f17fd1dfa3f4eb5e2b1c0a8d4e95c1a2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nout := range []uint32{0, 1, 300} {
+		want := change.NewClaimID(*wire.NewOutPoint(txHash, nout)).String()
+		if got := hex.EncodeToString(dbpkg.ClaimHashForOutpoint(txHash[:], nout)); got != want {
+			t.Errorf("Expected %s, got %s", want, got)
+		}
+	}
+}
+
+func TestClaimType(t *testing.T) {
+	tests := map[string]*pb.Claim{
+		dbpkg.ClaimTypeStream:     {Type: &pb.Claim_Stream{Stream: &pb.Stream{}}},
+		dbpkg.ClaimTypeChannel:    {Type: &pb.Claim_Channel{Channel: &pb.Channel{}}},
+		dbpkg.ClaimTypeRepost:     {Type: &pb.Claim_Repost{Repost: &pb.ClaimReference{}}},
+		dbpkg.ClaimTypeCollection: {Type: &pb.Claim_Collection{Collection: &pb.ClaimList{}}},
+		"":                        {},
+	}
+	for want, claim := range tests {
+		if got := dbpkg.ClaimType(claim); got != want {
+			t.Errorf("Expected %q, got %q", want, got)
+		}
+	}
+}
@@ -3,15 +3,21 @@ package db
 // db_get.go contains the basic access functions to the database.
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"log"
 	"math"
+	"strings"
 
+	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/lbryio/herald.go/db/prefixes"
 	"github.com/lbryio/herald.go/db/stack"
 	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcd/wire"
 	"github.com/linxGnu/grocksdb"
 )
 
@@ -33,6 +39,14 @@ func GetExpirationHeightFull(lastUpdatedHeight uint32, extended bool) uint32 {
 	return lastUpdatedHeight + ExtendedClaimExpirationTime
 }
 
+// GetClaimExpirationHeight returns the expiration height of a claim last
+// updated at height. Claims which had not expired by the extended
+// expiration fork got the extended expiration time.
+func GetClaimExpirationHeight(height uint32) uint32 {
+	extended := height+OriginalClaimExpirationTime >= ExtendedClaimExpirationForkHeight
+	return GetExpirationHeightFull(height, extended)
+}
+
 // EnsureHandle is a helper function to ensure that the db has a handle to the given column family.
 func (db *ReadOnlyDBColumnFamily) EnsureHandle(prefix byte) (*grocksdb.ColumnFamilyHandle, error) {
 	cfName := string(prefix)
@@ -148,6 +162,7 @@ func (db *ReadOnlyDBColumnFamily) GetBalance(hashX []byte) (uint64, uint64, erro
 
 type TXOInfo struct {
 	TxHash *chainhash.Hash
+	TxNum  uint32
 	TxPos  uint16
 	Height uint32
 	Value  uint64
@@ -201,6 +216,7 @@ func (db *ReadOnlyDBColumnFamily) GetUnspent(hashX []byte) ([]TXOInfo, error) {
 		results = append(results,
 			TXOInfo{
 				TxHash: txhashValue.TxHash,
+				TxNum:  utxoKey.TxNum,
 				TxPos:  utxoKey.Nout,
 				Height: stack.BisectRight(db.TxCounts, []uint32{utxoKey.TxNum})[0],
 				Value:  utxoValue.Amount,
@@ -267,6 +283,55 @@ func (db *ReadOnlyDBColumnFamily) GetHistory(hashX []byte) ([]TxInfo, error) {
 	return results, nil
 }
 
+// GetHistoryPage returns up to limit history entries of hashX with heights
+// in [fromHeight, toHeight], ordered by height. Entries sharing a HashXHistory
+// row are never split across pages, so a page may exceed limit slightly. If
+// more entries remain, the height to resume from is returned as next.
+func (db *ReadOnlyDBColumnFamily) GetHistoryPage(hashX []byte, fromHeight, toHeight uint32, limit int) (results []TxInfo, next *uint32, err error) {
+	handle, err := db.EnsureHandle(prefixes.HashXHistory)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := &prefixes.HashXHistoryKey{
+		Prefix: []byte{prefixes.HashXHistory},
+		HashX:  hashX,
+		Height: fromHeight,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithStart(key.PackKey()).WithIncludeValue(true)
+	// The page usually ends before the history does.
+	defer options.Grp.Stop()
+
+	results = make([]TxInfo, 0, limit)
+	for kv := range IterCF(db.DB, options) {
+		historyKey := kv.Key.(*prefixes.HashXHistoryKey)
+		if historyKey.Height > toHeight {
+			break
+		}
+		if len(results) >= limit {
+			height := historyKey.Height
+			next = &height
+			break
+		}
+		historyValue := kv.Value.(*prefixes.HashXHistoryValue)
+		for _, txNum := range historyValue.TxNums {
+			rawTxHash, err := db.GetTxHash(txNum)
+			if err != nil {
+				return nil, nil, err
+			}
+			txHash, err := chainhash.NewHash(rawTxHash)
+			if err != nil {
+				return nil, nil, err
+			}
+			results = append(results, TxInfo{
+				TxHash: txHash,
+				Height: historyKey.Height,
+			})
+		}
+	}
+	return results, next, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) GetStatus(hashX []byte) ([]byte, error) {
 	// Lookup in HashXMempoolStatus first.
 	status, err := db.getMempoolStatus(hashX)
@@ -333,6 +398,75 @@ func (db *ReadOnlyDBColumnFamily) getMempoolStatus(hashX []byte) ([]byte, error)
 	return value.Status, nil
 }
 
+// GetStatuses returns the status of each hashX, like GetStatus does for a
+// single one. Indexed statuses are looked up in bulk, HashXMempoolStatus
+// taking precedence over HashXStatus. Any hashX without an indexed status
+// falls back to GetStatus.
+func (db *ReadOnlyDBColumnFamily) GetStatuses(hashXs [][]byte) ([][]byte, error) {
+	statuses := make([][]byte, len(hashXs))
+	if len(hashXs) == 0 {
+		return statuses, nil
+	}
+
+	// HashXMempoolStatus rows share the HashXStatus key and value layout.
+	multiGet := func(prefix byte) error {
+		handle, err := db.EnsureHandle(prefix)
+		if err != nil {
+			return err
+		}
+		idxs := make([]int, 0, len(hashXs))
+		rawKeys := make([][]byte, 0, len(hashXs))
+		for i, hashX := range hashXs {
+			if statuses[i] != nil {
+				continue
+			}
+			key := &prefixes.HashXStatusKey{Prefix: []byte{prefix}, HashX: hashX}
+			idxs = append(idxs, i)
+			rawKeys = append(rawKeys, key.PackKey())
+		}
+		if len(rawKeys) == 0 {
+			return nil
+		}
+		slices, err := db.DB.MultiGetCF(db.Opts, handle, rawKeys...)
+		defer slices.Destroy()
+		if err != nil {
+			return err
+		}
+		for j, slice := range slices {
+			if slice.Size() == 0 {
+				continue
+			}
+			rawValue := make([]byte, len(slice.Data()))
+			copy(rawValue, slice.Data())
+			value := prefixes.HashXStatusValue{}
+			value.UnpackValue(rawValue)
+			statuses[idxs[j]] = value.Status
+		}
+		return nil
+	}
+
+	err := multiGet(prefixes.HashXMempoolStatus)
+	if err != nil {
+		return nil, err
+	}
+	err = multiGet(prefixes.HashXStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	// No indexed status. Fall back to enumerating HashXHistory.
+	for i, hashX := range hashXs {
+		if statuses[i] != nil {
+			continue
+		}
+		statuses[i], err = db.GetStatus(hashX)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return statuses, nil
+}
+
 // GetStreamsAndChannelRepostedByChannelHashes returns a map of streams and channel hashes that are reposted by the given channel hashes.
 func (db *ReadOnlyDBColumnFamily) GetStreamsAndChannelRepostedByChannelHashes(reposterChannelHashes [][]byte) (map[string][]byte, map[string][]byte, error) {
 	handle, err := db.EnsureHandle(prefixes.ChannelToClaim)
@@ -462,6 +596,75 @@ func (db *ReadOnlyDBColumnFamily) GetRepost(claimHash []byte) ([]byte, error) {
 	return value.RepostedClaimHash, nil
 }
 
+// RepostInfo is a claim reposting another claim.
+type RepostInfo struct {
+	ClaimHash   []byte
+	TxNum       uint32
+	Position    uint16
+	Height      uint32
+	ChannelHash []byte
+}
+
+// GetReposts returns up to limit claims from RepostedClaim which repost
+// claimHash, skipping the first offset.
+func (db *ReadOnlyDBColumnFamily) GetReposts(claimHash []byte, offset, limit int) ([]RepostInfo, error) {
+	handle, err := db.EnsureHandle(prefixes.RepostedClaim)
+	if err != nil {
+		return nil, err
+	}
+	key := prefixes.NewRepostedKey(claimHash)
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	results := make([]RepostInfo, 0, limit)
+	for kv := range IterCF(db.DB, options) {
+		if len(results) >= limit {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		repostedKey := kv.Key.(*prefixes.RepostedKey)
+		repostedValue := kv.Value.(*prefixes.RepostedValue)
+		channelHash, err := db.GetChannelForClaim(repostedValue.ClaimHash, repostedKey.TxNum, repostedKey.Position)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, RepostInfo{
+			ClaimHash:   repostedValue.ClaimHash,
+			TxNum:       repostedKey.TxNum,
+			Position:    repostedKey.Position,
+			Height:      stack.BisectRight(db.TxCounts, []uint32{repostedKey.TxNum})[0],
+			ChannelHash: channelHash,
+		})
+	}
+	return results, nil
+}
+
+// GetRepostChain follows Repost from claimHash and returns the claims it
+// reposts, nearest first, up to maxDepth of them. If the chain loops back
+// on itself it stops there and cycle is true.
+func (db *ReadOnlyDBColumnFamily) GetRepostChain(claimHash []byte, maxDepth int) (chain [][]byte, cycle bool, err error) {
+	seen := map[string]bool{string(claimHash): true}
+	chain = make([][]byte, 0)
+	for len(chain) < maxDepth {
+		claimHash, err = db.GetRepost(claimHash)
+		if err != nil {
+			return nil, false, err
+		} else if claimHash == nil {
+			break
+		}
+		if seen[string(claimHash)] {
+			return chain, true, nil
+		}
+		seen[string(claimHash)] = true
+		chain = append(chain, claimHash)
+	}
+	return chain, false, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) GetRepostedCount(claimHash []byte) (int, error) {
 	handle, err := db.EnsureHandle(prefixes.RepostedCount)
 	if err != nil {
@@ -582,6 +785,75 @@ func (db *ReadOnlyDBColumnFamily) GetSupportAmount(claimHash []byte) (uint64, er
 	return value.Amount, nil
 }
 
+// SupportInfo is a single support for a claim.
+type SupportInfo struct {
+	TxHash           *chainhash.Hash
+	TxNum            uint32
+	Position         uint16
+	Height           uint32
+	Amount           uint64
+	ActivationHeight uint32
+}
+
+// GetSupports returns up to limit supports for claimHash from ClaimToSupport,
+// skipping the first offset. Supports which are not yet active are skipped
+// unless includePending is set.
+func (db *ReadOnlyDBColumnFamily) GetSupports(claimHash []byte, includePending bool, offset, limit int) ([]SupportInfo, error) {
+	handle, err := db.EnsureHandle(prefixes.ClaimToSupport)
+	if err != nil {
+		return nil, err
+	}
+	height := db.Height
+	if db.LastState != nil {
+		height = db.LastState.Height
+	}
+
+	key := &prefixes.ClaimToSupportKey{
+		Prefix:    []byte{prefixes.ClaimToSupport},
+		ClaimHash: claimHash,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	results := make([]SupportInfo, 0, limit)
+	for kv := range IterCF(db.DB, options) {
+		if len(results) >= limit {
+			break
+		}
+		supportKey := kv.Key.(*prefixes.ClaimToSupportKey)
+		supportValue := kv.Value.(*prefixes.ClaimToSupportValue)
+		activation, err := db.GetActivationFull(supportKey.TxNum, supportKey.Position, true)
+		if err != nil {
+			return nil, err
+		}
+		if !includePending && activation > height {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		rawTxHash, err := db.GetTxHash(supportKey.TxNum)
+		if err != nil {
+			return nil, err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, SupportInfo{
+			TxHash:           txHash,
+			TxNum:            supportKey.TxNum,
+			Position:         supportKey.Position,
+			Height:           stack.BisectRight(db.TxCounts, []uint32{supportKey.TxNum})[0],
+			Amount:           supportValue.Amount,
+			ActivationHeight: activation,
+		})
+	}
+	return results, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) GetTxHash(txNum uint32) ([]byte, error) {
 	// TODO: caching
 	handle, err := db.EnsureHandle(prefixes.TxHash)
@@ -605,6 +877,255 @@ func (db *ReadOnlyDBColumnFamily) GetTxHash(txNum uint32) ([]byte, error) {
 	return rawValue, nil
 }
 
+// GetTxNum returns the tx number of the given tx hash, or nil if the tx
+// is not confirmed.
+func (db *ReadOnlyDBColumnFamily) GetTxNum(txHash *chainhash.Hash) (*prefixes.TxNumValue, error) {
+	handle, err := db.EnsureHandle(prefixes.TxNum)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &prefixes.TxNumKey{Prefix: []byte{prefixes.TxNum}, TxHash: txHash}
+	rawKey := key.PackKey()
+	slice, err := db.DB.GetCF(db.Opts, handle, rawKey)
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	}
+	if slice.Size() == 0 {
+		return nil, nil
+	}
+
+	rawValue := make([]byte, len(slice.Data()))
+	copy(rawValue, slice.Data())
+	return prefixes.TxNumValueUnpack(rawValue), nil
+}
+
+// GetMempoolTx returns the raw mempool tx with the given hash, or nil if
+// it is not in the mempool.
+func (db *ReadOnlyDBColumnFamily) GetMempoolTx(txHash *chainhash.Hash) ([]byte, error) {
+	handle, err := db.EnsureHandle(prefixes.MempoolTx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &prefixes.MempoolTxKey{Prefix: []byte{prefixes.MempoolTx}, TxHash: txHash[:]}
+	rawKey := key.PackKey()
+	slice, err := db.DB.GetCF(db.Opts, handle, rawKey)
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	}
+	if slice.Size() == 0 {
+		return nil, nil
+	}
+
+	rawValue := make([]byte, len(slice.Data()))
+	copy(rawValue, slice.Data())
+	return rawValue, nil
+}
+
+// GetTx returns the raw confirmed tx with the given hash, or nil if it is
+// not in the db.
+func (db *ReadOnlyDBColumnFamily) GetTx(txHash *chainhash.Hash) ([]byte, error) {
+	handle, err := db.EnsureHandle(prefixes.Tx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &prefixes.TxKey{Prefix: []byte{prefixes.Tx}, TxHash: txHash}
+	rawKey := key.PackKey()
+	slice, err := db.DB.GetCF(db.Opts, handle, rawKey)
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	}
+	if slice.Size() == 0 {
+		return nil, nil
+	}
+
+	rawValue := make([]byte, len(slice.Data()))
+	copy(rawValue, slice.Data())
+	return rawValue, nil
+}
+
+// OutpointStatus describes a transaction output as seen by the db and mempool.
+type OutpointStatus struct {
+	// Height of the funding tx, 0 if it is in the mempool.
+	Height uint32
+	Amount uint64
+	// Spent is set once the output has left the UTXO set.
+	Spent bool
+	// SpenderTxHash is set if a mempool tx spends the output.
+	SpenderTxHash *chainhash.Hash
+}
+
+// GetOutpointStatus looks up an output in UTXO / HashXUTXO, falling back to
+// the mempool for unconfirmed funding txs. Mempool spends are looked up in
+// mempoolSpenders, as returned by GetMempoolSpenders. Returns nil if the
+// output is not known at all.
+func (db *ReadOnlyDBColumnFamily) GetOutpointStatus(txHash *chainhash.Hash, nout uint16, mempoolSpenders map[wire.OutPoint]chainhash.Hash) (*OutpointStatus, error) {
+	outpoint := wire.OutPoint{Hash: *txHash, Index: uint32(nout)}
+	txNum, err := db.GetTxNum(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if txNum == nil {
+		rawTx, err := db.GetMempoolTx(txHash)
+		if err != nil || rawTx == nil {
+			return nil, err
+		}
+		var tx wire.MsgTx
+		err = tx.Deserialize(bytes.NewReader(rawTx))
+		if err != nil {
+			return nil, err
+		}
+		if int(nout) >= len(tx.TxOut) {
+			return nil, nil
+		}
+		status := &OutpointStatus{Height: 0, Amount: uint64(tx.TxOut[nout].Value)}
+		if spender, ok := mempoolSpenders[outpoint]; ok {
+			status.SpenderTxHash = &spender
+		}
+		return status, nil
+	}
+
+	status := &OutpointStatus{
+		Height: stack.BisectRight(db.TxCounts, []uint32{txNum.TxNum})[0],
+	}
+
+	// HashXUTXO rows are removed when the output is spent.
+	handle, err := db.EnsureHandle(prefixes.HashXUTXO)
+	if err != nil {
+		return nil, err
+	}
+	hashXKey := &prefixes.HashXUTXOKey{
+		Prefix:      []byte{prefixes.HashXUTXO},
+		ShortTXHash: txHash[:4],
+		TxNum:       txNum.TxNum,
+		Nout:        nout,
+	}
+	slice, err := db.DB.GetCF(db.Opts, handle, hashXKey.PackKey())
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	}
+	if slice.Size() == 0 {
+		status.Spent = true
+		return status, nil
+	}
+	rawValue := make([]byte, len(slice.Data()))
+	copy(rawValue, slice.Data())
+	hashXValue := prefixes.HashXUTXOValueUnpack(rawValue)
+
+	handle, err = db.EnsureHandle(prefixes.UTXO)
+	if err != nil {
+		return nil, err
+	}
+	utxoKey := &prefixes.UTXOKey{
+		Prefix: []byte{prefixes.UTXO},
+		HashX:  hashXValue.HashX,
+		TxNum:  txNum.TxNum,
+		Nout:   nout,
+	}
+	utxoSlice, err := db.DB.GetCF(db.Opts, handle, utxoKey.PackKey())
+	defer utxoSlice.Free()
+	if err != nil {
+		return nil, err
+	}
+	if utxoSlice.Size() == 0 {
+		status.Spent = true
+		return status, nil
+	}
+	rawValue = make([]byte, len(utxoSlice.Data()))
+	copy(rawValue, utxoSlice.Data())
+	status.Amount = prefixes.UTXOValueUnpack(rawValue).Amount
+
+	if spender, ok := mempoolSpenders[outpoint]; ok {
+		status.SpenderTxHash = &spender
+	}
+	return status, nil
+}
+
+// GetMempoolSpenders maps each output spent by a mempool tx to the
+// spending tx, in one pass over the mempool.
+func (db *ReadOnlyDBColumnFamily) GetMempoolSpenders() (map[wire.OutPoint]chainhash.Hash, error) {
+	handle, err := db.EnsureHandle(prefixes.MempoolTx)
+	if err != nil {
+		return nil, err
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.MempoolTx})
+	options = options.WithIncludeValue(true)
+	spenders := make(map[wire.OutPoint]chainhash.Hash)
+	for kv := range IterCF(db.DB, options) {
+		value := kv.Value.(*prefixes.MempoolTxValue)
+		var tx wire.MsgTx
+		err := tx.Deserialize(bytes.NewReader(value.RawTx))
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		spender := tx.TxHash()
+		for _, txIn := range tx.TxIn {
+			spenders[txIn.PreviousOutPoint] = spender
+		}
+	}
+	return spenders, nil
+}
+
+// GetSpender finds the confirmed tx spending outpoint among the history of
+// hashX, the script it pays to, from fromHeight on. At most maxTxs txs of
+// the history are read. Returns nil if it is not found.
+func (db *ReadOnlyDBColumnFamily) GetSpender(outpoint *wire.OutPoint, hashX []byte, fromHeight uint32, maxTxs int) (*chainhash.Hash, uint32, error) {
+	handle, err := db.EnsureHandle(prefixes.HashXHistory)
+	if err != nil {
+		return nil, 0, err
+	}
+	key := &prefixes.HashXHistoryKey{
+		Prefix: []byte{prefixes.HashXHistory},
+		HashX:  hashX,
+		Height: fromHeight,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithStart(key.PackKey()).WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	for kv := range IterCF(db.DB, options) {
+		historyKey := kv.Key.(*prefixes.HashXHistoryKey)
+		historyValue := kv.Value.(*prefixes.HashXHistoryValue)
+		for _, txNum := range historyValue.TxNums {
+			if maxTxs <= 0 {
+				return nil, 0, nil
+			}
+			maxTxs--
+			rawTxHash, err := db.GetTxHash(txNum)
+			if err != nil {
+				return nil, 0, err
+			}
+			txHash, err := chainhash.NewHash(rawTxHash)
+			if err != nil {
+				return nil, 0, err
+			}
+			rawTx, err := db.GetTx(txHash)
+			if err != nil {
+				return nil, 0, err
+			} else if rawTx == nil {
+				continue
+			}
+			var tx wire.MsgTx
+			if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+				return nil, 0, err
+			}
+			for _, txIn := range tx.TxIn {
+				if txIn.PreviousOutPoint == *outpoint {
+					return txHash, historyKey.Height, nil
+				}
+			}
+		}
+	}
+	return nil, 0, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) GetActivation(txNum uint32, postition uint16) (uint32, error) {
 	return db.GetActivationFull(txNum, postition, false)
 }
@@ -629,6 +1150,8 @@ func (db *ReadOnlyDBColumnFamily) GetActivationFull(txNum uint32, postition uint
 	defer slice.Free()
 	if err != nil {
 		return 0, err
+	} else if slice.Size() == 0 {
+		return 0, nil
 	}
 	rawValue := make([]byte, len(slice.Data()))
 	copy(rawValue, slice.Data())
@@ -637,6 +1160,52 @@ func (db *ReadOnlyDBColumnFamily) GetActivationFull(txNum uint32, postition uint
 	return value.Height, nil
 }
 
+// ExpiringClaim is a claim from the ClaimExpiration index.
+type ExpiringClaim struct {
+	ClaimHash        []byte
+	NormalizedName   string
+	TxNum            uint32
+	Position         uint16
+	ExpirationHeight uint32
+}
+
+// GetClaimsExpiring returns up to limit claims expiring in
+// [fromHeight, toHeight] in expiration order, skipping the first offset.
+func (db *ReadOnlyDBColumnFamily) GetClaimsExpiring(fromHeight, toHeight uint32, offset, limit int) ([]ExpiringClaim, error) {
+	handle, err := db.EnsureHandle(prefixes.ClaimExpiration)
+	if err != nil {
+		return nil, err
+	}
+	key := &prefixes.ClaimExpirationKey{
+		Prefix:     []byte{prefixes.ClaimExpiration},
+		Expiration: fromHeight,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.ClaimExpiration})
+	options = options.WithStart(key.PartialPack(1)).WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	results := make([]ExpiringClaim, 0, limit)
+	for kv := range IterCF(db.DB, options) {
+		expirationKey := kv.Key.(*prefixes.ClaimExpirationKey)
+		if expirationKey.Expiration > toHeight || len(results) >= limit {
+			break
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		expirationValue := kv.Value.(*prefixes.ClaimExpirationValue)
+		results = append(results, ExpiringClaim{
+			ClaimHash:        expirationValue.ClaimHash,
+			NormalizedName:   expirationValue.NormalizedName,
+			TxNum:            expirationKey.TxNum,
+			Position:         expirationKey.Position,
+			ExpirationHeight: expirationKey.Expiration,
+		})
+	}
+	return results, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) GetClaimTxo(claim []byte) (*prefixes.ClaimToTXOValue, error) {
 	return db.GetCachedClaimTxo(claim, false)
 }
@@ -706,6 +1275,110 @@ func (db *ReadOnlyDBColumnFamily) GetControllingClaim(name string) (*prefixes.Cl
 	return value, nil
 }
 
+// NameTakeover is the controlling claim of a name.
+type NameTakeover struct {
+	NormalizedName     string
+	ClaimHash          []byte
+	LastTakeoverHeight uint32
+}
+
+// GetNamesByPrefix returns up to limit names starting with prefix which
+// have a controlling claim, in byte order, beginning with the first name
+// after the cursor after. ClaimTakeover keys are ordered by name length
+// first, so the names of each length are merged. A single iterator is used,
+// seeking at most once per name length and once per name returned.
+func (db *ReadOnlyDBColumnFamily) GetNamesByPrefix(prefix, after string, limit int) ([]NameTakeover, error) {
+	handle, err := db.EnsureHandle(prefixes.ClaimTakeover)
+	if err != nil {
+		return nil, err
+	}
+	ro := grocksdb.NewDefaultReadOptions()
+	defer ro.Destroy()
+	it := db.DB.NewIteratorCF(ro, handle)
+	defer it.Close()
+
+	// seek returns the first name of length nameLen which is at least
+	// start, is greater than after and starts with prefix. If there is none
+	// it returns the length of the next names to look at, or 0 if there are
+	// no more names.
+	seek := func(nameLen int, start, after string) (*NameTakeover, int) {
+		rawKey := make([]byte, 3+len(start))
+		rawKey[0] = prefixes.ClaimTakeover
+		binary.BigEndian.PutUint16(rawKey[1:], uint16(nameLen))
+		copy(rawKey[3:], start)
+		for it.Seek(rawKey); it.Valid(); it.Next() {
+			key := it.Key()
+			keyData := key.Data()
+			if len(keyData) < 3 || keyData[0] != prefixes.ClaimTakeover {
+				key.Free()
+				return nil, 0
+			}
+			keyLen := int(binary.BigEndian.Uint16(keyData[1:]))
+			name := string(keyData[3:])
+			key.Free()
+			if keyLen != nameLen {
+				return nil, keyLen
+			} else if !strings.HasPrefix(name, prefix) {
+				return nil, nameLen + 1
+			} else if name <= after {
+				continue
+			}
+			value := it.Value()
+			takeover := prefixes.ClaimTakeoverValueUnpack(append([]byte(nil), value.Data()...))
+			value.Free()
+			return &NameTakeover{
+				NormalizedName:     name,
+				ClaimHash:          takeover.ClaimHash,
+				LastTakeoverHeight: takeover.Height,
+			}, nameLen
+		}
+		return nil, 0
+	}
+
+	// Find the first name of each length.
+	heads := make([]*NameTakeover, 0)
+	nameLen := len(prefix)
+	if nameLen == 0 {
+		nameLen = 1
+	}
+	for nameLen > 0 && nameLen <= txscript.MaxClaimNameSize {
+		start := after
+		if len(start) > nameLen {
+			start = start[:nameLen]
+		}
+		if start < prefix {
+			start = prefix
+		}
+		head, next := seek(nameLen, start, after)
+		if head != nil {
+			heads = append(heads, head)
+			next = nameLen + 1
+		}
+		nameLen = next
+	}
+
+	results := make([]NameTakeover, 0, limit)
+	for len(results) < limit && len(heads) > 0 {
+		i := 0
+		for j := range heads {
+			if heads[j].NormalizedName < heads[i].NormalizedName {
+				i = j
+			}
+		}
+		name := heads[i].NormalizedName
+		results = append(results, *heads[i])
+		if next, _ := seek(len(name), name, name); next != nil {
+			heads[i] = next
+		} else {
+			heads = append(heads[:i], heads[i+1:]...)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) FsGetClaimByHash(claimHash []byte) (*ResolveResult, error) {
 	claim, err := db.GetCachedClaimTxo(claimHash, true)
 	if err != nil {
@@ -727,7 +1400,7 @@ func (db *ReadOnlyDBColumnFamily) FsGetClaimByHash(claimHash []byte) (*ResolveRe
 		claim.RootTxNum,
 		claim.RootPosition,
 		activation,
-		claim.ChannelSignatureIsValid,
+		&claim.ChannelSignatureIsValid,
 	)
 }
 
@@ -791,6 +1464,151 @@ func (db *ReadOnlyDBColumnFamily) BidOrderNameIter(normalizedName string) <-chan
 	return ch
 }
 
+// NameClaimInfo is a claim competing for a name.
+type NameClaimInfo struct {
+	ClaimHash        []byte
+	Name             string
+	TxHash           *chainhash.Hash
+	TxNum            uint32
+	Position         uint16
+	Height           uint32
+	EffectiveAmount  uint64
+	ActivationHeight uint32
+	IsControlling    bool
+}
+
+// GetClaimsForName returns the active claims for normalizedName in bid
+// order, along with the height of the last takeover of the name.
+func (db *ReadOnlyDBColumnFamily) GetClaimsForName(normalizedName string) ([]NameClaimInfo, uint32, error) {
+	controllingClaim, err := db.GetControllingClaim(normalizedName)
+	if err != nil {
+		return nil, 0, err
+	}
+	var lastTakeoverHeight uint32
+	var controllingHash []byte
+	if controllingClaim != nil {
+		lastTakeoverHeight = controllingClaim.Height
+		controllingHash = controllingClaim.ClaimHash
+	}
+
+	// BidOrderNameIter returns a nil channel if the handle is missing.
+	if _, err := db.EnsureHandle(prefixes.BidOrder); err != nil {
+		return nil, 0, err
+	}
+	results := make([]NameClaimInfo, 0)
+	for kv := range db.BidOrderNameIter(normalizedName) {
+		key := kv.Key.(*prefixes.BidOrderKey)
+		claimVal := kv.Value.(*prefixes.BidOrderValue)
+		activation, err := db.GetActivation(key.TxNum, key.Position)
+		if err != nil {
+			return nil, 0, err
+		}
+		name := key.NormalizedName
+		claimTxo, err := db.GetCachedClaimTxo(claimVal.ClaimHash, true)
+		if err != nil {
+			return nil, 0, err
+		} else if claimTxo != nil {
+			name = claimTxo.Name
+		}
+		rawTxHash, err := db.GetTxHash(key.TxNum)
+		if err != nil {
+			return nil, 0, err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, NameClaimInfo{
+			ClaimHash:        claimVal.ClaimHash,
+			Name:             name,
+			TxHash:           txHash,
+			TxNum:            key.TxNum,
+			Position:         key.Position,
+			Height:           stack.BisectRight(db.TxCounts, []uint32{key.TxNum})[0],
+			EffectiveAmount:  uint64(key.EffectiveAmount),
+			ActivationHeight: activation,
+			IsControlling:    bytes.Equal(claimVal.ClaimHash, controllingHash),
+		})
+	}
+	return results, lastTakeoverHeight, nil
+}
+
+// maxShortIdLen is the longest partial claim id in ClaimShortIdPrefix.
+const maxShortIdLen = 10
+
+// ShortIdClaim is a claim matching a partial claim id.
+type ShortIdClaim struct {
+	ClaimHash        []byte
+	Name             string
+	TxNum            uint32
+	Position         uint16
+	ShortUrl         string
+	ActivationHeight uint32
+}
+
+// GetClaimsByShortId returns the claims for normalizedName whose claim id
+// starts with partialClaimId, skipping offset and returning at most limit
+// claims. Matches are ordered by root TXO, oldest claim first.
+func (db *ReadOnlyDBColumnFamily) GetClaimsByShortId(normalizedName, partialClaimId string, offset, limit int) ([]ShortIdClaim, error) {
+	handle, err := db.EnsureHandle(prefixes.ClaimShortIdPrefix)
+	if err != nil {
+		return nil, err
+	}
+	shortId := partialClaimId
+	if len(shortId) > maxShortIdLen {
+		shortId = shortId[:maxShortIdLen]
+	}
+	key := prefixes.NewClaimShortIDKey(normalizedName, shortId)
+	var rawKeyPrefix []byte
+	if shortId != "" {
+		rawKeyPrefix = key.PartialPack(2)
+	} else {
+		rawKeyPrefix = key.PartialPack(1)
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(rawKeyPrefix)
+	options = options.WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	results := make([]ShortIdClaim, 0)
+	for kv := range IterCF(db.DB, options) {
+		if len(results) >= limit {
+			break
+		}
+		key := kv.Key.(*prefixes.ClaimShortIDKey)
+		value := kv.Value.(*prefixes.ClaimShortIDValue)
+		claim, err := db.GetCachedClaimHash(value.TxNum, value.Position)
+		if err != nil {
+			return nil, err
+		} else if claim == nil {
+			continue
+		}
+		if !strings.HasPrefix(hex.EncodeToString(claim.ClaimHash), partialClaimId) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		shortUrl, err := db.GetShortClaimIdUrl(claim.Name, normalizedName, claim.ClaimHash, key.RootTxNum, key.RootPosition)
+		if err != nil {
+			return nil, err
+		}
+		activation, err := db.GetActivation(value.TxNum, value.Position)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, ShortIdClaim{
+			ClaimHash:        claim.ClaimHash,
+			Name:             claim.Name,
+			TxNum:            value.TxNum,
+			Position:         value.Position,
+			ShortUrl:         shortUrl,
+			ActivationHeight: activation,
+		})
+	}
+	return results, nil
+}
+
 func (db *ReadOnlyDBColumnFamily) ClaimShortIdIter(normalizedName string, claimId string) <-chan *prefixes.PrefixRowKV {
 	handle, err := db.EnsureHandle(prefixes.ClaimShortIdPrefix)
 	if err != nil {
@@ -833,6 +1651,155 @@ func (db *ReadOnlyDBColumnFamily) GetCachedClaimHash(txNum uint32, position uint
 	return value, nil
 }
 
+// GetSupportToClaim returns the claim supported by the given TXO, or nil
+// if the TXO is not a support.
+func (db *ReadOnlyDBColumnFamily) GetSupportToClaim(txNum uint32, position uint16) (*prefixes.SupportToClaimValue, error) {
+	handle, err := db.EnsureHandle(prefixes.SupportToClaim)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &prefixes.SupportToClaimKey{
+		Prefix:   []byte{prefixes.SupportToClaim},
+		TxNum:    txNum,
+		Position: position,
+	}
+	rawKey := key.PackKey()
+
+	slice, err := db.DB.GetCF(db.Opts, handle, rawKey)
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	} else if slice.Size() == 0 {
+		return nil, nil
+	}
+
+	rawValue := make([]byte, len(slice.Data()))
+	copy(rawValue, slice.Data())
+	value := prefixes.SupportToClaimValueUnpack(rawValue)
+	return value, nil
+}
+
+const (
+	TXOTypeClaim   = "claim"
+	TXOTypeUpdate  = "update"
+	TXOTypeSupport = "support"
+)
+
+// TXOClaimInfo describes how a TXO relates to a claim.
+type TXOClaimInfo struct {
+	ClaimHash []byte
+	Name      string
+	// Type is one of TXOTypeClaim, TXOTypeUpdate or TXOTypeSupport.
+	Type string
+}
+
+// GetTXOClaimInfo looks up the TXO in TXOToClaim and SupportToClaim.
+// Returns nil if the TXO is neither a claim nor a support.
+func (db *ReadOnlyDBColumnFamily) GetTXOClaimInfo(txNum uint32, position uint16) (*TXOClaimInfo, error) {
+	claim, err := db.GetCachedClaimHash(txNum, position)
+	if err != nil {
+		return nil, err
+	}
+	if claim != nil {
+		info := &TXOClaimInfo{ClaimHash: claim.ClaimHash, Name: claim.Name, Type: TXOTypeUpdate}
+		claimTxo, err := db.GetClaimTxo(claim.ClaimHash)
+		if err != nil {
+			return nil, err
+		}
+		if claimTxo != nil && claimTxo.RootTxNum == txNum && claimTxo.RootPosition == position {
+			info.Type = TXOTypeClaim
+		}
+		return info, nil
+	}
+
+	support, err := db.GetSupportToClaim(txNum, position)
+	if err != nil || support == nil {
+		return nil, err
+	}
+	info := &TXOClaimInfo{ClaimHash: support.ClaimHash, Type: TXOTypeSupport}
+	claimTxo, err := db.GetClaimTxo(support.ClaimHash)
+	if err != nil {
+		return nil, err
+	}
+	if claimTxo != nil {
+		info.Name = claimTxo.Name
+	}
+	return info, nil
+}
+
+// AddressClaimsCacheSize is the number of addresses whose claims are kept
+// in memory for paging.
+const AddressClaimsCacheSize = 1000
+
+// AddressClaim is an unspent claim or support TXO of an address.
+type AddressClaim struct {
+	TxNum  uint32
+	TxPos  uint16
+	Height uint32
+	TXOClaimInfo
+}
+
+// cachedAddressClaims is the claims of an address at a block.
+type cachedAddressClaims struct {
+	tip    string
+	claims []AddressClaim
+}
+
+// NewAddressClaimsCache returns the cache used by GetAddressClaims.
+func NewAddressClaimsCache() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetCacheSizeLimit(AddressClaimsCacheSize)
+	cache.SkipTTLExtensionOnHit(true)
+	return cache
+}
+
+// GetAddressClaims returns the unspent claims and supports of hashX, in
+// UTXO order. They are read once per block and cached, so paging through
+// them doesn't read every UTXO of hashX again.
+func (db *ReadOnlyDBColumnFamily) GetAddressClaims(hashX []byte) ([]AddressClaim, error) {
+	tip := db.tipHash()
+	if db.AddressClaimsCache != nil {
+		if cached, err := db.AddressClaimsCache.Get(string(hashX)); err == nil {
+			if entry := cached.(*cachedAddressClaims); entry.tip == tip {
+				return entry.claims, nil
+			}
+		}
+	}
+
+	handle, err := db.EnsureHandle(prefixes.UTXO)
+	if err != nil {
+		return nil, err
+	}
+	key := &prefixes.UTXOKey{Prefix: []byte{prefixes.UTXO}, HashX: hashX}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	defer options.Grp.Stop()
+	claims := make([]AddressClaim, 0)
+	for kv := range IterCF(db.DB, options) {
+		utxoKey := kv.Key.(*prefixes.UTXOKey)
+		claimInfo, err := db.GetTXOClaimInfo(utxoKey.TxNum, utxoKey.Nout)
+		if err != nil {
+			return nil, err
+		} else if claimInfo == nil {
+			continue
+		}
+		claims = append(claims, AddressClaim{
+			TxNum:        utxoKey.TxNum,
+			TxPos:        utxoKey.Nout,
+			Height:       stack.BisectRight(db.TxCounts, []uint32{utxoKey.TxNum})[0],
+			TXOClaimInfo: *claimInfo,
+		})
+	}
+
+	if db.AddressClaimsCache != nil {
+		entry := &cachedAddressClaims{tip: tip, claims: claims}
+		if err := db.AddressClaimsCache.Set(string(hashX), entry); err != nil {
+			return nil, err
+		}
+	}
+	return claims, nil
+}
+
 // GetBlockerHash get the hash of the blocker or filterer of the claim.
 // TODO: this currently converts the byte arrays to strings, which is not
 // very efficient. Might want to figure out a better way to do this.
@@ -874,3 +1841,65 @@ func (db *ReadOnlyDBColumnFamily) GetBlockerHash(claimHash, repostedClaimHash, c
 
 	return blockedHash, filteredHash, nil
 }
+
+// ClaimDiff is the set of claims touched or deleted by a block.
+type ClaimDiff struct {
+	Height        uint32
+	BlockHash     []byte
+	TouchedClaims [][]byte
+	DeletedClaims [][]byte
+}
+
+// GetClaimDiffs returns the claim diffs of the blocks fromHeight through
+// toHeight, stopping at the tip. The writer puts a diff for every block but
+// only keeps the diffs of recent blocks, so an error is returned for a
+// block without one rather than an empty diff.
+func (db *ReadOnlyDBColumnFamily) GetClaimDiffs(fromHeight, toHeight uint32) ([]ClaimDiff, error) {
+	handle, err := db.EnsureHandle(prefixes.ClaimDiff)
+	if err != nil {
+		return nil, err
+	}
+	height := db.Height
+	if db.LastState != nil {
+		height = db.LastState.Height
+	}
+	if toHeight > height {
+		toHeight = height
+	}
+	if fromHeight > toHeight {
+		return []ClaimDiff{}, nil
+	}
+
+	diffs := make([]ClaimDiff, 0, toHeight-fromHeight+1)
+	for h := fromHeight; h <= toHeight; h++ {
+		blockHash, err := db.GetBlockHash(h)
+		if err != nil {
+			return nil, err
+		}
+		diffs = append(diffs, ClaimDiff{Height: h, BlockHash: blockHash})
+	}
+
+	startKey := &prefixes.TouchedOrDeletedClaimKey{
+		Prefix: []byte{prefixes.ClaimDiff},
+		Height: int32(fromHeight),
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.ClaimDiff})
+	options = options.WithStart(startKey.PartialPack(1)).WithIncludeValue(true)
+	defer options.Grp.Stop()
+	next := fromHeight
+	for kv := range IterCF(db.DB, options) {
+		key := kv.Key.(*prefixes.TouchedOrDeletedClaimKey)
+		if uint32(key.Height) != next || next > toHeight {
+			break
+		}
+		value := kv.Value.(*prefixes.TouchedOrDeletedClaimValue)
+		diff := &diffs[next-fromHeight]
+		diff.TouchedClaims = value.TouchedClaims
+		diff.DeletedClaims = value.DeletedClaims
+		next++
+	}
+	if next <= toHeight {
+		return nil, fmt.Errorf("claim diff of block %d is not kept", next)
+	}
+	return diffs, nil
+}
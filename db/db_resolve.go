@@ -9,7 +9,9 @@ import (
 	"math"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/ReneKroon/ttlcache/v2"
 	"github.com/lbryio/herald.go/db/prefixes"
 	"github.com/lbryio/herald.go/db/stack"
 	"github.com/lbryio/herald.go/internal"
@@ -28,7 +30,7 @@ func PrepareResolveResult(
 	rootTxNum uint32,
 	rootPosition uint16,
 	activationHeight uint32,
-	signatureValid bool) (*ResolveResult, error) {
+	signatureValid *bool) (*ResolveResult, error) {
 
 	normalizedName := internal.NormalizeName(name)
 	controllingClaim, err := db.GetControllingClaim(normalizedName)
@@ -135,6 +137,28 @@ func PrepareResolveResult(
 
 	isControlling := bytes.Equal(controllingClaim.ClaimHash, claimHash)
 
+	// The metadata is optional, legacy claims can't be decoded.
+	var claimValue *pb.Claim
+	if value, err := db.GetClaimValue(claimHash, txNum, position); err != nil {
+		log.Debugf("decoding claim %s: %v", hex.EncodeToString(claimHash), err)
+	} else {
+		claimValue = value.Claim
+	}
+
+	// The writer's verdict on the signature stands, it is only verified
+	// here when the caller has none.
+	var isSignatureValid bool
+	if signatureValid != nil {
+		isSignatureValid = *signatureValid
+	} else if claimValue != nil {
+		signature, err := db.GetClaimSignature(claimHash, txNum, position)
+		if err != nil {
+			log.Debugf("verifying claim %s: %v", hex.EncodeToString(claimHash), err)
+		} else if signature != nil {
+			isSignatureValid = signature.Valid
+		}
+	}
+
 	return &ResolveResult{
 		Name:               name,
 		NormalizedName:     normalizedName,
@@ -157,13 +181,15 @@ func PrepareResolveResult(
 		ClaimsInChannel:    claimsInChannel,
 		ChannelHash:        channelHash,
 		RepostedClaimHash:  repostedClaimHash,
-		SignatureValid:     signatureValid,
+		SignatureValid:     isSignatureValid,
 		RepostTxHash:       repostTxHash,
 		RepostTxPostition:  repostTxPostition,
 		RepostHeight:       repostHeight,
 		ChannelTxHash:      channelTxHash,
 		ChannelTxPostition: channelTxPostition,
 		ChannelHeight:      channelHeight,
+		TrendingScore:      db.GetTrendingScore(claimHash),
+		ClaimValue:         claimValue,
 	}, nil
 }
 
@@ -228,7 +254,7 @@ func (db *ReadOnlyDBColumnFamily) ResolveParsedUrl(parsed *PathSegment) (*Resolv
 				claimTxo.RootTxNum,
 				claimTxo.RootPosition,
 				activation,
-				claimTxo.ChannelSignatureIsValid,
+				&claimTxo.ChannelSignatureIsValid,
 			)
 		}
 		log.Println("nomalizedName:", normalizedName)
@@ -277,7 +303,7 @@ func (db *ReadOnlyDBColumnFamily) ResolveParsedUrl(parsed *PathSegment) (*Resolv
 			key.RootTxNum,
 			key.RootPosition,
 			activation,
-			signatureIsValid,
+			&signatureIsValid,
 		)
 	}
 
@@ -311,7 +337,7 @@ func (db *ReadOnlyDBColumnFamily) ResolveParsedUrl(parsed *PathSegment) (*Resolv
 			claimTxo.RootTxNum,
 			claimTxo.RootPosition,
 			activation,
-			claimTxo.ChannelSignatureIsValid,
+			&claimTxo.ChannelSignatureIsValid,
 		)
 	}
 
@@ -368,6 +394,380 @@ func (db *ReadOnlyDBColumnFamily) ResolveClaimInChannel(channelHash []byte, norm
 	}
 }
 
+// Orderings for GetClaimsInChannel. Name order is the order of
+// ChannelToClaim, by name length and then by name.
+const (
+	ChannelOrderName   = "name"
+	ChannelOrderHeight = "height"
+)
+
+// ChannelCacheSize is the number of channels whose claims are kept in
+// memory for paging in height order or in reverse.
+const ChannelCacheSize = 100
+
+// channelClaim is a row of ChannelToClaim.
+type channelClaim struct {
+	txNum     uint32
+	position  uint16
+	claimHash []byte
+}
+
+// cachedChannel is the claims of a channel at a block, in key order and in
+// height order.
+type cachedChannel struct {
+	tip      string
+	byKey    []channelClaim
+	byHeight []channelClaim
+}
+
+// NewChannelCache returns the cache used by GetClaimsInChannel.
+func NewChannelCache() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetCacheSizeLimit(ChannelCacheSize)
+	cache.SkipTTLExtensionOnHit(true)
+	return cache
+}
+
+// tipHash returns the hash of the block the db is at, or "" before the db
+// state is read. Cached entries are only valid at the tip they were read at.
+func (db *ReadOnlyDBColumnFamily) tipHash() string {
+	if db.LastState == nil || db.LastState.Tip == nil {
+		return ""
+	}
+	return string(db.LastState.Tip[:])
+}
+
+// channelClaimsPage returns up to limit rows of ChannelToClaim for
+// channelHash in key order, skipping the first offset.
+func (db *ReadOnlyDBColumnFamily) channelClaimsPage(channelHash []byte, offset, limit int) ([]channelClaim, error) {
+	handle, err := db.EnsureHandle(prefixes.ChannelToClaim)
+	if err != nil {
+		return nil, err
+	}
+	key := prefixes.NewChannelToClaimKeyWHash(channelHash)
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithIncludeValue(true)
+	defer options.Grp.Stop()
+	claims := make([]channelClaim, 0)
+	for row := range IterCF(db.DB, options) {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		key := row.Key.(*prefixes.ChannelToClaimKey)
+		claims = append(claims, channelClaim{
+			txNum:     key.TxNum,
+			position:  key.Position,
+			claimHash: row.Value.(*prefixes.ChannelToClaimValue).ClaimHash,
+		})
+		if len(claims) >= limit {
+			break
+		}
+	}
+	return claims, nil
+}
+
+// getCachedChannel returns every row of ChannelToClaim for channelHash.
+// The rows are read once per block and cached.
+func (db *ReadOnlyDBColumnFamily) getCachedChannel(channelHash []byte) (*cachedChannel, error) {
+	tip := db.tipHash()
+	if db.ChannelCache != nil {
+		if cached, err := db.ChannelCache.Get(string(channelHash)); err == nil {
+			if entry := cached.(*cachedChannel); entry.tip == tip {
+				return entry, nil
+			}
+		}
+	}
+
+	byKey, err := db.channelClaimsPage(channelHash, 0, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+	byHeight := make([]channelClaim, len(byKey))
+	copy(byHeight, byKey)
+	sort.Slice(byHeight, func(i, j int) bool {
+		if byHeight[i].txNum != byHeight[j].txNum {
+			return byHeight[i].txNum < byHeight[j].txNum
+		}
+		return byHeight[i].position < byHeight[j].position
+	})
+	entry := &cachedChannel{tip: tip, byKey: byKey, byHeight: byHeight}
+	if db.ChannelCache != nil {
+		if err := db.ChannelCache.Set(string(channelHash), entry); err != nil {
+			return nil, err
+		}
+	}
+	return entry, nil
+}
+
+// GetClaimsInChannel returns up to limit resolved claims signed by
+// channelHash from ChannelToClaim, skipping the first offset, along with
+// the total number of claims in the channel. Claims are ordered by name or
+// by height, newest last unless reverse is set. Pages in name order are
+// read straight from ChannelToClaim, other orders page through the cached
+// rows of the channel.
+func (db *ReadOnlyDBColumnFamily) GetClaimsInChannel(channelHash []byte, orderBy string, reverse bool, offset, limit int) ([]*ResolveResult, int, error) {
+	if orderBy != ChannelOrderName && orderBy != ChannelOrderHeight {
+		return nil, 0, fmt.Errorf("invalid order: %s", orderBy)
+	}
+	count, err := db.GetClaimsInChannelCount(channelHash)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := int(count)
+	if limit <= 0 || offset >= total {
+		return []*ResolveResult{}, total, nil
+	}
+
+	var page []channelClaim
+	if orderBy == ChannelOrderName && !reverse {
+		page, err = db.channelClaimsPage(channelHash, offset, limit)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else {
+		channel, err := db.getCachedChannel(channelHash)
+		if err != nil {
+			return nil, 0, err
+		}
+		claims := channel.byKey
+		if orderBy == ChannelOrderHeight {
+			claims = channel.byHeight
+		}
+		page = make([]channelClaim, 0, limit)
+		for i := offset; i < len(claims) && len(page) < limit; i++ {
+			if reverse {
+				page = append(page, claims[len(claims)-1-i])
+			} else {
+				page = append(page, claims[i])
+			}
+		}
+	}
+
+	results := make([]*ResolveResult, 0, len(page))
+	for _, claim := range page {
+		res, err := db.FsGetClaimByHash(claim.claimHash)
+		if err != nil {
+			return nil, 0, err
+		}
+		results = append(results, res)
+	}
+	return results, total, nil
+}
+
+// ChannelStats aggregates the claims signed by a channel.
+type ChannelStats struct {
+	ClaimCount      uint32
+	SupportAmount   uint64
+	EffectiveAmount uint64
+	// RepostCount is the number of reposts of the channel's claims.
+	RepostCount int
+	// EarliestHeight and LatestHeight are the creation heights of the
+	// channel's oldest and newest claims.
+	EarliestHeight uint32
+	LatestHeight   uint32
+}
+
+// ChannelStatsCacheSize is the number of channel stats kept in memory.
+const ChannelStatsCacheSize = 1000
+
+// channelStatsClaim is what a claim adds to the stats of its channel.
+type channelStatsClaim struct {
+	supportAmount   uint64
+	effectiveAmount uint64
+	repostCount     int
+	height          uint32
+}
+
+// cachedChannelStats is the stats of a channel at a block, along with what
+// each of its claims adds to them. The claims changed by later blocks are
+// taken out and added again rather than reading every claim.
+type cachedChannelStats struct {
+	mu        sync.Mutex
+	height    uint32
+	blockHash []byte
+	stats     *ChannelStats
+	claims    map[string]channelStatsClaim
+}
+
+// NewChannelStatsCache returns the cache used by GetChannelStats.
+func NewChannelStatsCache() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetCacheSizeLimit(ChannelStatsCacheSize)
+	cache.SkipTTLExtensionOnHit(true)
+	return cache
+}
+
+// GetChannelStats returns the stats of the claims in ChannelToClaim for
+// channelHash. The stats are computed once and cached, then updated from
+// the claim diffs of the blocks after them.
+func (db *ReadOnlyDBColumnFamily) GetChannelStats(channelHash []byte) (*ChannelStats, error) {
+	height := db.Height
+	if db.LastState != nil {
+		height = db.LastState.Height
+	}
+	if db.ChannelStatsCache != nil {
+		if cached, err := db.ChannelStatsCache.Get(string(channelHash)); err == nil {
+			entry := cached.(*cachedChannelStats)
+			entry.mu.Lock()
+			defer entry.mu.Unlock()
+			err := db.updateChannelStats(channelHash, entry, height)
+			if err == nil {
+				return entry.stats, nil
+			}
+			log.Debugf("computing stats of channel %x: %v", channelHash, err)
+		}
+	}
+	entry, err := db.getChannelStats(channelHash, height)
+	if err != nil {
+		return nil, err
+	}
+	if db.ChannelStatsCache != nil {
+		if err := db.ChannelStatsCache.Set(string(channelHash), entry); err != nil {
+			return nil, err
+		}
+	}
+	return entry.stats, nil
+}
+
+// channelStatsClaim reads what a claim created at rootTxNum adds to the
+// stats of its channel.
+func (db *ReadOnlyDBColumnFamily) channelStatsClaim(claimHash []byte, rootTxNum uint32) (channelStatsClaim, error) {
+	var claim channelStatsClaim
+	var err error
+	if claim.supportAmount, err = db.GetSupportAmount(claimHash); err != nil {
+		return claim, err
+	}
+	if claim.effectiveAmount, err = db.GetEffectiveAmount(claimHash, false); err != nil {
+		return claim, err
+	}
+	if claim.repostCount, err = db.GetRepostedCount(claimHash); err != nil {
+		return claim, err
+	}
+	claim.height = stack.BisectRight(db.TxCounts, []uint32{rootTxNum})[0]
+	return claim, nil
+}
+
+// sumChannelStats adds up the stats of the claims of a channel.
+func sumChannelStats(claims map[string]channelStatsClaim) *ChannelStats {
+	stats := &ChannelStats{ClaimCount: uint32(len(claims))}
+	first := true
+	for _, claim := range claims {
+		stats.SupportAmount += claim.supportAmount
+		stats.EffectiveAmount += claim.effectiveAmount
+		stats.RepostCount += claim.repostCount
+		if first || claim.height < stats.EarliestHeight {
+			stats.EarliestHeight = claim.height
+		}
+		if first || claim.height > stats.LatestHeight {
+			stats.LatestHeight = claim.height
+		}
+		first = false
+	}
+	return stats
+}
+
+// getChannelStats computes the stats of the claims in ChannelToClaim for
+// channelHash, at height.
+func (db *ReadOnlyDBColumnFamily) getChannelStats(channelHash []byte, height uint32) (*cachedChannelStats, error) {
+	handle, err := db.EnsureHandle(prefixes.ChannelToClaim)
+	if err != nil {
+		return nil, err
+	}
+	blockHash, err := db.GetBlockHash(height)
+	if err != nil {
+		return nil, err
+	}
+	entry := &cachedChannelStats{
+		height:    height,
+		blockHash: blockHash,
+		claims:    make(map[string]channelStatsClaim),
+	}
+
+	key := prefixes.NewChannelToClaimKeyWHash(channelHash)
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix(key.PartialPack(1))
+	options = options.WithIncludeValue(true)
+	defer options.Grp.Stop()
+	for row := range IterCF(db.DB, options) {
+		claimHash := row.Value.(*prefixes.ChannelToClaimValue).ClaimHash
+		rootTxNum := row.Key.(*prefixes.ChannelToClaimKey).TxNum
+		claimTxo, err := db.GetCachedClaimTxo(claimHash, true)
+		if err != nil {
+			return nil, err
+		} else if claimTxo != nil {
+			rootTxNum = claimTxo.RootTxNum
+		}
+		claim, err := db.channelStatsClaim(claimHash, rootTxNum)
+		if err != nil {
+			return nil, err
+		}
+		entry.claims[string(claimHash)] = claim
+	}
+	entry.stats = sumChannelStats(entry.claims)
+	return entry, nil
+}
+
+// updateChannelStats brings the stats of a channel up to height from the
+// claim diffs of the blocks after them. The writer marks a claim touched
+// when its supports, effective amount, signature or repost count change. An
+// error is returned if the stats have to be computed again, after a reorg or
+// when the diffs aren't kept.
+func (db *ReadOnlyDBColumnFamily) updateChannelStats(channelHash []byte, entry *cachedChannelStats, height uint32) error {
+	if entry.height == height {
+		return nil
+	} else if entry.height > height || height-entry.height > ReorgLimit {
+		return fmt.Errorf("stats at height %d can't be updated to %d", entry.height, height)
+	}
+	blockHash, err := db.GetBlockHash(entry.height)
+	if err != nil {
+		return err
+	} else if !bytes.Equal(blockHash, entry.blockHash) {
+		return fmt.Errorf("block %d was disconnected", entry.height)
+	}
+	diffs, err := db.GetClaimDiffs(entry.height+1, height)
+	if err != nil {
+		return err
+	}
+
+	claims := make(map[string]channelStatsClaim, len(entry.claims))
+	for claimHash, claim := range entry.claims {
+		claims[claimHash] = claim
+	}
+	for _, diff := range diffs {
+		for _, claimHash := range diff.DeletedClaims {
+			delete(claims, string(claimHash))
+		}
+		for _, claimHash := range diff.TouchedClaims {
+			delete(claims, string(claimHash))
+			claimTxo, err := db.GetCachedClaimTxo(claimHash, true)
+			if err != nil {
+				return err
+			} else if claimTxo == nil {
+				continue
+			}
+			signingHash, err := db.GetChannelForClaim(claimHash, claimTxo.TxNum, claimTxo.Position)
+			if err != nil {
+				return err
+			} else if !bytes.Equal(signingHash, channelHash) {
+				continue
+			}
+			claim, err := db.channelStatsClaim(claimHash, claimTxo.RootTxNum)
+			if err != nil {
+				return err
+			}
+			claims[string(claimHash)] = claim
+		}
+	}
+	if blockHash, err = db.GetBlockHash(height); err != nil {
+		return err
+	}
+	entry.height = height
+	entry.blockHash = blockHash
+	entry.claims = claims
+	entry.stats = sumChannelStats(claims)
+	return nil
+}
+
 func (db *ReadOnlyDBColumnFamily) Resolve(url string) *ExpandedResolveResult {
 	var res = NewExpandedResolveResult()
 
@@ -0,0 +1,369 @@
+package db
+
+// db_staked.go contains functions for ranking claims by effective amount.
+// The claims are ranked on startup, then the claims whose effective amount
+// was changed by a block are moved within the ranking. They are ranked again
+// in the background when too many claims dropped out of it.
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/lbryio/herald.go/db/prefixes"
+	"github.com/lbryio/herald.go/db/stack"
+	log "github.com/sirupsen/logrus"
+)
+
+// MaxTopStakedClaims is how many of the highest staked claims are ranked.
+// EffectiveAmount is keyed by claim hash, so ranking scans all of it.
+const MaxTopStakedClaims = 10000
+
+// topStakedReserve is how many claims are ranked past MaxTopStakedClaims,
+// so claims can drop out of the top without ranking again.
+const topStakedReserve = MaxTopStakedClaims / 10
+
+// StakedClaim is a claim hash and its effective amount, along with the
+// type of the claim and the height it was last updated at.
+type StakedClaim struct {
+	ClaimHash       []byte
+	EffectiveAmount uint64
+	// ClaimType is empty for claims which can't be decoded.
+	ClaimType string
+	Height    uint32
+}
+
+// TopStakedCache keeps the ranking of the highest staked claims, highest
+// first. Every claim left out of the ranking has at most the effective
+// amount of the last ranked claim.
+type TopStakedCache struct {
+	mu     sync.RWMutex
+	claims []StakedClaim
+	// complete is set when every staked claim is ranked.
+	complete bool
+	// stale is set when fewer than MaxTopStakedClaims claims are known to
+	// be the highest staked, the claims have to be ranked again.
+	stale bool
+	// ranking is set while the claims are ranked again, pending keeps the
+	// claims changed meanwhile to apply them to the new ranking.
+	ranking bool
+	pending []StakedClaim
+}
+
+// NewTopStakedCache returns an empty cache.
+func NewTopStakedCache() *TopStakedCache {
+	return &TopStakedCache{}
+}
+
+// Set replaces the ranking and applies the claims changed while it was
+// made. complete tells whether every staked claim is ranked.
+func (c *TopStakedCache) Set(claims []StakedClaim, complete bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.claims = claims
+	c.complete = complete
+	c.stale = false
+	c.update(c.pending)
+	c.ranking = false
+	c.pending = nil
+}
+
+// Update moves the changed claims within the ranking. Claims with no
+// effective amount are dropped.
+func (c *TopStakedCache) Update(changed []StakedClaim) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ranking {
+		c.pending = append(c.pending, changed...)
+	}
+	c.update(changed)
+}
+
+func (c *TopStakedCache) update(changed []StakedClaim) {
+	if len(changed) == 0 {
+		return
+	}
+	isChanged := make(map[string]bool, len(changed))
+	for _, claim := range changed {
+		isChanged[string(claim.ClaimHash)] = true
+	}
+	claims := make([]StakedClaim, 0, len(c.claims)+len(changed))
+	for _, claim := range c.claims {
+		if !isChanged[string(claim.ClaimHash)] {
+			claims = append(claims, claim)
+		}
+	}
+	for _, claim := range changed {
+		n := len(claims)
+		if claim.EffectiveAmount == 0 {
+			continue
+		}
+		// A claim below the last ranked claim may be below claims which
+		// were left out, unless every claim is ranked.
+		if !c.complete && (n == 0 || claim.EffectiveAmount < claims[n-1].EffectiveAmount) {
+			continue
+		}
+		i := sort.Search(n, func(i int) bool { return claims[i].EffectiveAmount < claim.EffectiveAmount })
+		claims = append(claims, StakedClaim{})
+		copy(claims[i+1:], claims[i:])
+		claims[i] = claim
+		if len(claims) > MaxTopStakedClaims+topStakedReserve {
+			claims = claims[:MaxTopStakedClaims+topStakedReserve]
+			c.complete = false
+		}
+	}
+	c.claims = claims
+	if !c.complete && len(c.claims) < MaxTopStakedClaims {
+		c.stale = true
+	}
+}
+
+// ranks reports whether a claim with the given effective amount could be
+// added to the ranking.
+func (c *TopStakedCache) ranks(amount uint64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	n := len(c.claims)
+	return c.complete || c.ranking || (n > 0 && amount >= c.claims[n-1].EffectiveAmount)
+}
+
+// MarkStale marks the claims as needing to be ranked again. It is safe to
+// call on nil.
+func (c *TopStakedCache) MarkStale() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stale = true
+}
+
+// Stale reports whether the claims need to be ranked again. It is safe to
+// call on nil.
+func (c *TopStakedCache) Stale() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stale
+}
+
+// startRanking reports whether the claims should be ranked again, it is
+// false if they are already being ranked.
+func (c *TopStakedCache) startRanking() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.ranking {
+		return false
+	}
+	c.ranking = true
+	c.pending = nil
+	return true
+}
+
+// stopRanking drops a ranking which failed. The claims stay stale.
+func (c *TopStakedCache) stopRanking() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ranking = false
+	c.pending = nil
+}
+
+type stakedHeap []StakedClaim
+
+func (h stakedHeap) Len() int            { return len(h) }
+func (h stakedHeap) Less(i, j int) bool  { return h[i].EffectiveAmount < h[j].EffectiveAmount }
+func (h stakedHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *stakedHeap) Push(x interface{}) { *h = append(*h, x.(StakedClaim)) }
+func (h *stakedHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// fillStakedClaim sets the type and height of a claim from its txo.
+func (db *ReadOnlyDBColumnFamily) fillStakedClaim(claim *StakedClaim, claimTxo *prefixes.ClaimToTXOValue) {
+	claim.Height = stack.BisectRight(db.TxCounts, []uint32{claimTxo.TxNum})[0]
+	if value, err := db.GetClaimValue(claim.ClaimHash, claimTxo.TxNum, claimTxo.Position); err == nil {
+		claim.ClaimType = ClaimType(value.Claim)
+	}
+}
+
+// rankStakedClaims returns the claims with the highest effective amounts,
+// highest first, and whether every staked claim was ranked. Abandoned
+// claims are dropped.
+func (db *ReadOnlyDBColumnFamily) rankStakedClaims() ([]StakedClaim, bool, error) {
+	handle, err := db.EnsureHandle(prefixes.EffectiveAmount)
+	if err != nil {
+		return nil, false, err
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.EffectiveAmount})
+	options = options.WithIncludeValue(true)
+
+	size := MaxTopStakedClaims + topStakedReserve
+	complete := true
+	h := make(stakedHeap, 0, size+1)
+	for kv := range IterCF(db.DB, options) {
+		amount := kv.Value.(*prefixes.EffectiveAmountValue).ActivatedSum
+		if amount == 0 {
+			continue
+		} else if len(h) == size && amount <= h[0].EffectiveAmount {
+			complete = false
+			continue
+		}
+		claimHash := make([]byte, len(kv.Key.(*prefixes.EffectiveAmountKey).ClaimHash))
+		copy(claimHash, kv.Key.(*prefixes.EffectiveAmountKey).ClaimHash)
+		heap.Push(&h, StakedClaim{ClaimHash: claimHash, EffectiveAmount: amount})
+		if len(h) > size {
+			heap.Pop(&h)
+			complete = false
+		}
+	}
+	ranked := make([]StakedClaim, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		ranked[i] = heap.Pop(&h).(StakedClaim)
+	}
+
+	claims := make([]StakedClaim, 0, len(ranked))
+	for _, claim := range ranked {
+		claimTxo, err := db.GetCachedClaimTxo(claim.ClaimHash, true)
+		if err != nil {
+			log.Warnf("skipping staked claim %x: %v", claim.ClaimHash, err)
+			continue
+		} else if claimTxo == nil {
+			continue
+		}
+		db.fillStakedClaim(&claim, claimTxo)
+		claims = append(claims, claim)
+	}
+	return claims, complete, nil
+}
+
+// InitTopStaked ranks the highest staked claims of the db, which is at
+// height. It is called on startup, and in the background when the ranking
+// goes stale.
+func (db *ReadOnlyDBColumnFamily) InitTopStaked(height uint32) error {
+	if db.TopStaked == nil {
+		return nil
+	}
+	claims, complete, err := db.rankStakedClaims()
+	if err != nil {
+		return err
+	}
+	db.TopStaked.Set(claims, complete)
+	log.Debugf("ranked %d staked claims at height %d", len(claims), height)
+	return nil
+}
+
+// rankTopStakedAsync ranks the highest staked claims again in the
+// background, unless they are already being ranked. Errors are logged.
+func (db *ReadOnlyDBColumnFamily) rankTopStakedAsync(height uint32) {
+	if db.TopStaked == nil || !db.TopStaked.startRanking() {
+		return
+	}
+	db.Grp.Add(1)
+	go func() {
+		defer db.Grp.Done()
+		if err := db.InitTopStaked(height); err != nil {
+			log.Error("ranking top staked claims:", err)
+			db.TopStaked.stopRanking()
+		}
+	}()
+}
+
+// advanceTopStaked moves the claims whose effective amount was changed by
+// the block at height within the ranking, given the undo ops of the block.
+// If the undo ops are missing the ranking is marked stale.
+func (db *ReadOnlyDBColumnFamily) advanceTopStaked(height uint32, undo []byte) error {
+	if db.TopStaked == nil {
+		return nil
+	} else if undo == nil {
+		log.Warnf("no undo ops for block %d, top staked claims will be ranked", height)
+		db.TopStaked.MarkStale()
+		return nil
+	}
+	seen := make(map[string]bool)
+	var changed []StakedClaim
+	err := walkUndoOps(undo, func(isPut bool, key, value []byte) error {
+		if key[0] != prefixes.EffectiveAmount || seen[string(key)] {
+			return nil
+		}
+		seen[string(key)] = true
+		k := &prefixes.EffectiveAmountKey{}
+		k.UnpackKey(key)
+		claim := StakedClaim{ClaimHash: make([]byte, len(k.ClaimHash))}
+		copy(claim.ClaimHash, k.ClaimHash)
+		amount, err := db.GetEffectiveAmount(claim.ClaimHash, false)
+		if err != nil {
+			return err
+		}
+		// Claims which can't be ranked are only dropped.
+		if amount > 0 && db.TopStaked.ranks(amount) {
+			claimTxo, err := db.GetCachedClaimTxo(claim.ClaimHash, true)
+			if err != nil {
+				return err
+			} else if claimTxo != nil {
+				claim.EffectiveAmount = amount
+				db.fillStakedClaim(&claim, claimTxo)
+			}
+		}
+		changed = append(changed, claim)
+		return nil
+	})
+	if err != nil {
+		db.TopStaked.MarkStale()
+		return err
+	}
+	db.TopStaked.Update(changed)
+	return nil
+}
+
+// unwindTopStaked marks the ranking stale, as the effective amounts of a
+// disconnected block can't be taken back out.
+func (db *ReadOnlyDBColumnFamily) unwindTopStaked() {
+	db.TopStaked.MarkStale()
+}
+
+// GetTopStakedClaims returns the claims with the highest effective amounts,
+// highest first, skipping offset and returning at most limit claims. If
+// claimType is set only claims of that type are returned, and only claims
+// last updated at or after minHeight. Only the MaxTopStakedClaims highest
+// staked claims are ranked, so the filters apply within them and offset
+// plus limit can't exceed MaxTopStakedClaims.
+func (db *ReadOnlyDBColumnFamily) GetTopStakedClaims(claimType string, minHeight uint32, offset, limit int) ([]StakedClaim, error) {
+	if offset+limit > MaxTopStakedClaims {
+		return nil, fmt.Errorf("only the %d highest staked claims are ranked", MaxTopStakedClaims)
+	}
+	var claims []StakedClaim
+	if db.TopStaked == nil {
+		var err error
+		if claims, _, err = db.rankStakedClaims(); err != nil {
+			return nil, err
+		}
+	} else {
+		db.TopStaked.mu.RLock()
+		defer db.TopStaked.mu.RUnlock()
+		claims = db.TopStaked.claims
+	}
+	if len(claims) > MaxTopStakedClaims {
+		claims = claims[:MaxTopStakedClaims]
+	}
+	results := make([]StakedClaim, 0, limit)
+	for _, claim := range claims {
+		if len(results) >= limit {
+			break
+		}
+		if claim.Height < minHeight || (claimType != "" && claim.ClaimType != claimType) {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		results = append(results, claim)
+	}
+	return results, nil
+}
@@ -0,0 +1,60 @@
+package db_test
+
+import (
+	"testing"
+
+	dbpkg "github.com/lbryio/herald.go/db"
+)
+
+func TestTopStakedCacheUpdate(t *testing.T) {
+	staked := func(id byte, amount uint64) dbpkg.StakedClaim {
+		return dbpkg.StakedClaim{ClaimHash: []byte{id}, EffectiveAmount: amount}
+	}
+	cache := dbpkg.NewTopStakedCache()
+	db := &dbpkg.ReadOnlyDBColumnFamily{TopStaked: cache}
+	ranked := func() string {
+		claims, err := db.GetTopStakedClaims("", 0, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]byte, 0, len(claims))
+		for _, claim := range claims {
+			ids = append(ids, claim.ClaimHash[0])
+		}
+		return string(ids)
+	}
+
+	// Every claim is ranked, so claims are added at any amount.
+	cache.Set([]dbpkg.StakedClaim{staked('a', 300), staked('b', 200), staked('c', 100)}, true)
+	cache.Update([]dbpkg.StakedClaim{staked('d', 150), staked('e', 50)})
+	if got := ranked(); got != "abdce" {
+		t.Errorf("Expected abdce, got %s", got)
+	}
+	cache.Update([]dbpkg.StakedClaim{staked('a', 0), staked('c', 500)})
+	if got := ranked(); got != "cbde" || cache.Stale() {
+		t.Errorf("Expected cbde, got %s (stale %v)", got, cache.Stale())
+	}
+
+	// Claims left out of the ranking have at most the amount of the last
+	// ranked claim, so a claim below it can't be placed.
+	cache.Set([]dbpkg.StakedClaim{staked('a', 300), staked('b', 200), staked('c', 100)}, false)
+	cache.Update([]dbpkg.StakedClaim{staked('d', 250)})
+	if got := ranked(); got != "adbc" {
+		t.Errorf("Expected adbc, got %s", got)
+	}
+	cache.Update([]dbpkg.StakedClaim{staked('e', 50), staked('a', 10)})
+	if got := ranked(); got != "dbc" {
+		t.Errorf("Expected dbc, got %s", got)
+	}
+	if !cache.Stale() {
+		t.Error("Expected a short ranking to be stale")
+	}
+	cache.Set([]dbpkg.StakedClaim{staked('a', 300)}, true)
+	if cache.Stale() {
+		t.Error("Expected a new ranking not to be stale")
+	}
+
+	if _, err := db.GetTopStakedClaims("", 0, dbpkg.MaxTopStakedClaims, 1); err == nil {
+		t.Error("Expected an error past the ranked claims")
+	}
+}
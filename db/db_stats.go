@@ -0,0 +1,312 @@
+package db
+
+// db_stats.go contains the claimtrie totals reported by server.stats. They
+// are counted once on startup and kept up to date on each block from the
+// block's undo ops. The changes made by recent blocks are kept to take them
+// back out when the blocks are disconnected.
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	"github.com/lbryio/herald.go/db/prefixes"
+	log "github.com/sirupsen/logrus"
+)
+
+// ClaimtrieStats are the claimtrie totals at a height.
+type ClaimtrieStats struct {
+	Height      uint32
+	Claims      int64
+	Supports    int64
+	ActiveNames int64
+	// TotalStaked is the sum of the activated amounts of every claim, in
+	// dewies.
+	TotalStaked int64
+}
+
+// add adds the totals of delta to s.
+func (s *ClaimtrieStats) add(delta ClaimtrieStats) {
+	s.Claims += delta.Claims
+	s.Supports += delta.Supports
+	s.ActiveNames += delta.ActiveNames
+	s.TotalStaked += delta.TotalStaked
+}
+
+// sub subtracts the totals of delta from s.
+func (s *ClaimtrieStats) sub(delta ClaimtrieStats) {
+	s.Claims -= delta.Claims
+	s.Supports -= delta.Supports
+	s.ActiveNames -= delta.ActiveNames
+	s.TotalStaked -= delta.TotalStaked
+}
+
+// StatsCache holds the current claimtrie totals, and the changes made by
+// the last ReorgLimit blocks.
+type StatsCache struct {
+	mu     sync.RWMutex
+	stats  ClaimtrieStats
+	deltas map[uint32]ClaimtrieStats
+	// stale is set when the totals can't be updated from the blocks, they
+	// have to be counted again.
+	stale bool
+}
+
+// NewStatsCache returns an empty stats cache.
+func NewStatsCache() *StatsCache {
+	return &StatsCache{deltas: make(map[uint32]ClaimtrieStats)}
+}
+
+// Get returns the current totals. It is safe to call on nil.
+func (c *StatsCache) Get() ClaimtrieStats {
+	if c == nil {
+		return ClaimtrieStats{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stats
+}
+
+// Set replaces the totals and the changes made by recent blocks, and clears
+// the stale flag.
+func (c *StatsCache) Set(stats ClaimtrieStats, deltas map[uint32]ClaimtrieStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats = stats
+	c.deltas = deltas
+	c.stale = false
+}
+
+// Apply adds the change made by the block at height to the totals.
+func (c *StatsCache) Apply(height uint32, delta ClaimtrieStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stats.add(delta)
+	c.stats.Height = height
+	c.deltas[height] = delta
+	if height >= ReorgLimit {
+		delete(c.deltas, height-ReorgLimit)
+	}
+}
+
+// Unwind takes the change made by the disconnected block at height back out
+// of the totals. If the change wasn't kept the totals are marked stale. It
+// is safe to call on nil.
+func (c *StatsCache) Unwind(height uint32) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delta, ok := c.deltas[height]
+	if !ok || c.stats.Height != height {
+		c.stale = true
+		return
+	}
+	delete(c.deltas, height)
+	c.stats.sub(delta)
+	c.stats.Height = height - 1
+}
+
+// MarkStale marks the totals as needing to be counted again.
+func (c *StatsCache) MarkStale() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stale = true
+}
+
+// Stale reports whether the totals need to be counted again. It is safe to
+// call on nil.
+func (c *StatsCache) Stale() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.stale
+}
+
+// walkUndoOps calls fn with each op of a block's undo ops. Each op is packed
+// as '>BLL' (is_put, key length, value length) followed by the key and the
+// value. The undo ops invert the ops of the block, so an undo put restores a
+// row the block deleted and an undo delete removes a row the block put.
+func walkUndoOps(undo []byte, fn func(isPut bool, key, value []byte) error) error {
+	for offset := 0; offset < len(undo); {
+		if len(undo)-offset < 9 {
+			return fmt.Errorf("truncated undo op at %d", offset)
+		}
+		isPut := undo[offset] == 1
+		keyLen := int(binary.BigEndian.Uint32(undo[offset+1:]))
+		valueLen := int(binary.BigEndian.Uint32(undo[offset+5:]))
+		offset += 9
+		if keyLen == 0 || len(undo)-offset < keyLen+valueLen {
+			return fmt.Errorf("truncated undo op at %d", offset)
+		}
+		key := undo[offset : offset+keyLen]
+		value := undo[offset+keyLen : offset+keyLen+valueLen]
+		offset += keyLen + valueLen
+		if err := fn(isPut, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UndoStatsDelta returns the change in the claimtrie totals made by a block,
+// given its undo ops.
+func UndoStatsDelta(undo []byte) (ClaimtrieStats, error) {
+	var delta ClaimtrieStats
+	err := walkUndoOps(undo, func(isPut bool, key, value []byte) error {
+		var sign int64 = 1
+		if isPut {
+			sign = -1
+		}
+		switch key[0] {
+		case prefixes.ClaimToTXO:
+			delta.Claims += sign
+		case prefixes.SupportToClaim:
+			delta.Supports += sign
+		case prefixes.ClaimTakeover:
+			delta.ActiveNames += sign
+		case prefixes.EffectiveAmount:
+			if len(value) < 8 {
+				return fmt.Errorf("invalid effective amount value %x", value)
+			}
+			delta.TotalStaked += sign * int64(binary.BigEndian.Uint64(value))
+		}
+		return nil
+	})
+	return delta, err
+}
+
+// countRows returns the number of rows with the given prefix.
+func (db *ReadOnlyDBColumnFamily) countRows(prefix byte) (int64, error) {
+	handle, err := db.EnsureHandle(prefix)
+	if err != nil {
+		return 0, err
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefix})
+	options = options.WithRawKey(true).WithIncludeValue(false)
+	var count int64
+	for range IterCF(db.DB, options) {
+		count++
+	}
+	return count, nil
+}
+
+// countStats counts the claimtrie totals from every row of the db.
+func (db *ReadOnlyDBColumnFamily) countStats() (ClaimtrieStats, error) {
+	var stats ClaimtrieStats
+	var err error
+	if stats.Claims, err = db.countRows(prefixes.ClaimToTXO); err != nil {
+		return stats, err
+	}
+	if stats.Supports, err = db.countRows(prefixes.SupportToClaim); err != nil {
+		return stats, err
+	}
+	if stats.ActiveNames, err = db.countRows(prefixes.ClaimTakeover); err != nil {
+		return stats, err
+	}
+
+	handle, err := db.EnsureHandle(prefixes.EffectiveAmount)
+	if err != nil {
+		return stats, err
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.EffectiveAmount})
+	options = options.WithIncludeKey(false).WithIncludeValue(true)
+	for kv := range IterCF(db.DB, options) {
+		stats.TotalStaked += int64(kv.Value.(*prefixes.EffectiveAmountValue).ActivatedSum)
+	}
+	return stats, nil
+}
+
+// blockUndo returns the undo ops of the block at height, or nil if they are
+// missing.
+func (db *ReadOnlyDBColumnFamily) blockUndo(height uint32) ([]byte, error) {
+	handle, err := db.EnsureHandle(prefixes.Undo)
+	if err != nil {
+		return nil, err
+	}
+	key := &prefixes.UndoKey{Prefix: []byte{prefixes.Undo}, Height: uint64(height)}
+	slice, err := db.DB.GetCF(db.Opts, handle, key.PackKey())
+	defer slice.Free()
+	if err != nil {
+		return nil, err
+	} else if slice.Size() == 0 {
+		return nil, nil
+	}
+	undo := make([]byte, slice.Size())
+	copy(undo, slice.Data())
+	return undo, nil
+}
+
+// blockStatsDelta returns the change in the claimtrie totals made by the
+// block at height, or false if the undo ops of the block are missing.
+func (db *ReadOnlyDBColumnFamily) blockStatsDelta(height uint32) (ClaimtrieStats, bool, error) {
+	undo, err := db.blockUndo(height)
+	if err != nil || undo == nil {
+		return ClaimtrieStats{}, false, err
+	}
+	delta, err := UndoStatsDelta(undo)
+	if err != nil {
+		return ClaimtrieStats{}, false, err
+	}
+	return delta, true, nil
+}
+
+// InitStats counts the claimtrie totals of the db, which is at height, and
+// reads the changes made by the last ReorgLimit blocks.
+func (db *ReadOnlyDBColumnFamily) InitStats(height uint32) error {
+	stats, err := db.countStats()
+	if err != nil {
+		return err
+	}
+	stats.Height = height
+	deltas := make(map[uint32]ClaimtrieStats)
+	for h := height; h > 0 && height-h < ReorgLimit; h-- {
+		delta, ok, err := db.blockStatsDelta(h)
+		if err != nil {
+			return err
+		} else if !ok {
+			break
+		}
+		deltas[h] = delta
+	}
+	if db.Stats == nil {
+		db.Stats = NewStatsCache()
+	}
+	db.Stats.Set(stats, deltas)
+	log.Infof("claimtrie stats at height %d: %d claims, %d supports, %d names",
+		height, stats.Claims, stats.Supports, stats.ActiveNames)
+	return nil
+}
+
+// advanceStats applies the undo ops of a new block to the totals. If the
+// undo ops of the block are missing the totals are marked stale.
+func (db *ReadOnlyDBColumnFamily) advanceStats(height uint32, undo []byte) error {
+	if db.Stats == nil {
+		return nil
+	} else if undo == nil {
+		log.Warnf("no undo ops for block %d, claimtrie stats will be counted", height)
+		db.Stats.MarkStale()
+		return nil
+	}
+	delta, err := UndoStatsDelta(undo)
+	if err != nil {
+		db.Stats.MarkStale()
+		return err
+	}
+	db.Stats.Apply(height, delta)
+	return nil
+}
+
+// unwindStats takes the change made by a disconnected block back out of
+// the totals.
+func (db *ReadOnlyDBColumnFamily) unwindStats(height uint32) {
+	db.Stats.Unwind(height)
+}
+
+// GetStats returns the current claimtrie totals.
+func (db *ReadOnlyDBColumnFamily) GetStats() ClaimtrieStats {
+	return db.Stats.Get()
+}
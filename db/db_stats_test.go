@@ -0,0 +1,117 @@
+package db_test
+
+import (
+	"encoding/binary"
+	"testing"
+
+	dbpkg "github.com/lbryio/herald.go/db"
+	"github.com/lbryio/herald.go/db/prefixes"
+)
+
+// packUndoOp packs an undo op the way the writer does.
+func packUndoOp(isPut bool, key, value []byte) []byte {
+	op := make([]byte, 9, 9+len(key)+len(value))
+	if isPut {
+		op[0] = 1
+	}
+	binary.BigEndian.PutUint32(op[1:], uint32(len(key)))
+	binary.BigEndian.PutUint32(op[5:], uint32(len(value)))
+	op = append(op, key...)
+	return append(op, value...)
+}
+
+func effectiveAmount(claimHash byte, amount uint64) ([]byte, []byte) {
+	key := append([]byte{prefixes.EffectiveAmount}, make([]byte, 20)...)
+	key[1] = claimHash
+	value := (&prefixes.EffectiveAmountValue{ActivatedSum: amount}).PackValue()
+	return key, value
+}
+
+func TestUndoStatsDelta(t *testing.T) {
+	var undo []byte
+	// The block added a claim and a name, and abandoned a support.
+	undo = append(undo, packUndoOp(false, []byte{prefixes.ClaimToTXO, 1}, []byte{0})...)
+	undo = append(undo, packUndoOp(false, []byte{prefixes.ClaimTakeover, 1}, []byte{0})...)
+	undo = append(undo, packUndoOp(true, []byte{prefixes.SupportToClaim, 1}, []byte{0})...)
+	// A claim was updated, which deletes and puts its row.
+	undo = append(undo, packUndoOp(false, []byte{prefixes.ClaimToTXO, 2}, []byte{1})...)
+	undo = append(undo, packUndoOp(true, []byte{prefixes.ClaimToTXO, 2}, []byte{0})...)
+	// A stake went from 100 to 250 and a new claim staked 50.
+	key, value := effectiveAmount(1, 250)
+	undo = append(undo, packUndoOp(false, key, value)...)
+	key, value = effectiveAmount(1, 100)
+	undo = append(undo, packUndoOp(true, key, value)...)
+	key, value = effectiveAmount(2, 50)
+	undo = append(undo, packUndoOp(false, key, value)...)
+	// Other rows don't count.
+	undo = append(undo, packUndoOp(false, []byte{prefixes.TXOToClaim, 1}, []byte{0})...)
+
+	delta, err := dbpkg.UndoStatsDelta(undo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dbpkg.ClaimtrieStats{Claims: 1, Supports: -1, ActiveNames: 1, TotalStaked: 200}
+	if delta != want {
+		t.Errorf("Expected %+v, got %+v", want, delta)
+	}
+
+	if _, err := dbpkg.UndoStatsDelta(undo[:len(undo)-1]); err == nil {
+		t.Error("Expected an error for truncated undo ops")
+	}
+
+	var nilStats *dbpkg.StatsCache
+	if nilStats.Get() != (dbpkg.ClaimtrieStats{}) {
+		t.Error("Expected nil stats to be empty")
+	}
+}
+
+func TestStatsCacheUnwind(t *testing.T) {
+	stats := dbpkg.NewStatsCache()
+	stats.Set(dbpkg.ClaimtrieStats{Height: 10, Claims: 5, Supports: 2}, map[uint32]dbpkg.ClaimtrieStats{})
+	stats.Apply(11, dbpkg.ClaimtrieStats{Claims: 2, TotalStaked: 100})
+	stats.Apply(12, dbpkg.ClaimtrieStats{Claims: -1, Supports: 1, ActiveNames: 1})
+
+	want := dbpkg.ClaimtrieStats{Height: 12, Claims: 6, Supports: 3, ActiveNames: 1, TotalStaked: 100}
+	if got := stats.Get(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	// Disconnecting the blocks takes their changes back out.
+	stats.Unwind(12)
+	stats.Unwind(11)
+	want = dbpkg.ClaimtrieStats{Height: 10, Claims: 5, Supports: 2}
+	if got := stats.Get(); got != want || stats.Stale() {
+		t.Errorf("Expected %+v, got %+v (stale %v)", want, got, stats.Stale())
+	}
+
+	// The change of block 10 wasn't kept, so the totals must be counted.
+	stats.Unwind(10)
+	if !stats.Stale() {
+		t.Error("Expected stale stats")
+	}
+	stats.Set(dbpkg.ClaimtrieStats{Height: 9}, map[uint32]dbpkg.ClaimtrieStats{})
+	if stats.Stale() {
+		t.Error("Expected counted stats not to be stale")
+	}
+
+	// Only the changes of the last ReorgLimit blocks are kept.
+	for height := uint32(10); height < 10+dbpkg.ReorgLimit+1; height++ {
+		stats.Apply(height, dbpkg.ClaimtrieStats{Claims: 1})
+	}
+	for height := uint32(10 + dbpkg.ReorgLimit); height > 10; height-- {
+		stats.Unwind(height)
+	}
+	if got := stats.Get(); got.Height != 10 || got.Claims != 1 || stats.Stale() {
+		t.Errorf("Unexpected stats %+v (stale %v)", got, stats.Stale())
+	}
+	stats.Unwind(10)
+	if !stats.Stale() {
+		t.Error("Expected stale stats past the kept changes")
+	}
+
+	var nilStats *dbpkg.StatsCache
+	nilStats.Unwind(1)
+	if nilStats.Stale() {
+		t.Error("Expected nil stats not to be stale")
+	}
+}
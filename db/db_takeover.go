@@ -0,0 +1,192 @@
+package db
+
+// db_takeover.go contains functions relevant to forecasting takeovers.
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/lbryio/herald.go/db/prefixes"
+)
+
+// GetActivationDelay returns the number of blocks a claim made at height
+// waits before it activates, for a name last taken over at
+// lastTakeoverHeight. Claims for names without a controlling claim
+// activate immediately.
+func GetActivationDelay(height, lastTakeoverHeight uint32, hasControllingClaim bool) uint32 {
+	if !hasControllingClaim || height < lastTakeoverHeight {
+		return 0
+	}
+	delay := (height - lastTakeoverHeight) / ProportionalDelayFactor
+	if delay > MaxTakeoverDelay {
+		return MaxTakeoverDelay
+	}
+	return delay
+}
+
+// PendingActivation is a claim or support which has not activated yet.
+type PendingActivation struct {
+	ClaimHash        []byte
+	TxNum            uint32
+	Position         uint16
+	IsSupport        bool
+	Amount           uint64
+	ActivationHeight uint32
+}
+
+// TakeoverForecast is the projected control of a name.
+type TakeoverForecast struct {
+	Height             uint32
+	ControllingClaim   []byte
+	LastTakeoverHeight uint32
+	Pending            []PendingActivation
+	// NewClaimActivationHeight is when a claim made in the next block
+	// would activate.
+	NewClaimActivationHeight uint32
+	// TakeoverClaim and TakeoverHeight are set if the pending activations
+	// change the controlling claim.
+	TakeoverClaim  []byte
+	TakeoverHeight uint32
+}
+
+// GetPendingActivations returns the claims and supports for normalizedName
+// from PendingActivation which activate after height, in activation order.
+func (db *ReadOnlyDBColumnFamily) GetPendingActivations(normalizedName string, height uint32) ([]PendingActivation, error) {
+	handle, err := db.EnsureHandle(prefixes.PendingActivation)
+	if err != nil {
+		return nil, err
+	}
+	amountHandle, err := db.EnsureHandle(prefixes.ActiveAmount)
+	if err != nil {
+		return nil, err
+	}
+
+	startKey := &prefixes.PendingActivationKey{
+		Prefix: []byte{prefixes.PendingActivation},
+		Height: height + 1,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.PendingActivation})
+	options = options.WithStart(startKey.PartialPack(1)).WithIncludeValue(true)
+	defer options.Grp.Stop()
+
+	results := make([]PendingActivation, 0)
+	for kv := range IterCF(db.DB, options) {
+		key := kv.Key.(*prefixes.PendingActivationKey)
+		value := kv.Value.(*prefixes.PendingActivationValue)
+		if value.NormalizedName != normalizedName {
+			continue
+		}
+		// The amount is recorded in ActiveAmount under the activation height.
+		amountKey := prefixes.NewActiveAmountKey(value.ClaimHash, key.TxoType, key.Height)
+		amountKey.TxNum = key.TxNum
+		amountKey.Position = key.Position
+		slice, err := db.DB.GetCF(db.Opts, amountHandle, amountKey.PackKey())
+		if err != nil {
+			return nil, err
+		}
+		var amount uint64
+		if slice.Size() > 0 {
+			amount = prefixes.ActiveAmountValueUnpack(slice.Data()).Amount
+		}
+		slice.Free()
+		results = append(results, PendingActivation{
+			ClaimHash:        value.ClaimHash,
+			TxNum:            key.TxNum,
+			Position:         key.Position,
+			IsSupport:        key.IsSupport(),
+			Amount:           amount,
+			ActivationHeight: key.Height,
+		})
+	}
+	return results, nil
+}
+
+// GetTakeoverForecast projects who will control normalizedName once the
+// pending claims and supports activate. As on chain, a takeover activates
+// every remaining pending claim and support for the name immediately.
+func (db *ReadOnlyDBColumnFamily) GetTakeoverForecast(normalizedName string) (*TakeoverForecast, error) {
+	height := db.Height
+	if db.LastState != nil {
+		height = db.LastState.Height
+	}
+	controllingClaim, err := db.GetControllingClaim(normalizedName)
+	if err != nil {
+		return nil, err
+	}
+	claims, lastTakeoverHeight, err := db.GetClaimsForName(normalizedName)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := db.GetPendingActivations(normalizedName, height)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := &TakeoverForecast{
+		Height:             height,
+		LastTakeoverHeight: lastTakeoverHeight,
+		Pending:            pending,
+	}
+	if controllingClaim != nil {
+		forecast.ControllingClaim = controllingClaim.ClaimHash
+	}
+	forecast.NewClaimActivationHeight = height + 1 +
+		GetActivationDelay(height+1, lastTakeoverHeight, controllingClaim != nil)
+
+	// Effective amounts of the active claims in bid order, and the
+	// supports waiting for their claim to activate.
+	amounts := make(map[string]uint64)
+	order := make([]string, 0, len(claims))
+	waiting := make(map[string]uint64)
+	for _, claim := range claims {
+		claimId := hex.EncodeToString(claim.ClaimHash)
+		amounts[claimId] = claim.EffectiveAmount
+		order = append(order, claimId)
+	}
+	activate := func(p PendingActivation) {
+		claimId := hex.EncodeToString(p.ClaimHash)
+		if _, ok := amounts[claimId]; ok {
+			amounts[claimId] += p.Amount
+		} else if p.IsSupport {
+			waiting[claimId] += p.Amount
+		} else {
+			amounts[claimId] = p.Amount + waiting[claimId]
+			order = append(order, claimId)
+			delete(waiting, claimId)
+		}
+	}
+	// The controlling claim keeps the name on a tie, otherwise the
+	// earliest claim wins.
+	winner := func(controlling []byte) []byte {
+		best := hex.EncodeToString(controlling)
+		bestAmount, ok := amounts[best]
+		for _, claimId := range order {
+			if !ok || amounts[claimId] > bestAmount {
+				best, bestAmount, ok = claimId, amounts[claimId], true
+			}
+		}
+		if !ok {
+			return nil
+		}
+		claimHash, _ := hex.DecodeString(best)
+		return claimHash
+	}
+
+	controlling := forecast.ControllingClaim
+	for i := 0; i < len(pending); {
+		activationHeight := pending[i].ActivationHeight
+		for ; i < len(pending) && pending[i].ActivationHeight == activationHeight; i++ {
+			activate(pending[i])
+		}
+		if next := winner(controlling); !bytes.Equal(next, controlling) {
+			for ; i < len(pending); i++ {
+				activate(pending[i])
+			}
+			if next = winner(controlling); !bytes.Equal(next, controlling) {
+				forecast.TakeoverClaim = next
+				forecast.TakeoverHeight = activationHeight
+			}
+		}
+	}
+	return forecast, nil
+}
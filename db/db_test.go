@@ -6,12 +6,15 @@ import (
 	"encoding/hex"
 	"log"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 
 	dbpkg "github.com/lbryio/herald.go/db"
 	"github.com/lbryio/herald.go/db/prefixes"
 	"github.com/lbryio/herald.go/internal"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
 	"github.com/lbryio/lbry.go/v3/extras/stop"
 	"github.com/linxGnu/grocksdb"
 )
@@ -438,6 +441,72 @@ func TestGetRepost(t *testing.T) {
 	}
 }
 
+func TestGetReposts(t *testing.T) {
+	claimHash, _ := hex.DecodeString("2556ed1cab9d17f2a9392030a9ad7f5d138f11bd")
+	filePath := "../testdata/WI_resolve.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	reposts, err := db.GetReposts(claimHash, 0, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(reposts) != 5 {
+		t.Fatalf("Expected 5 reposts, got %d", len(reposts))
+	}
+	if reposts[0].ChannelHash != nil {
+		t.Errorf("Expected no channel, got %x", reposts[0].ChannelHash)
+	}
+
+	reposts, err = db.GetReposts(claimHash, 1, 1)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(reposts) != 1 {
+		t.Fatalf("Expected 1 repost, got %d", len(reposts))
+	}
+	want := "1b845565203eca16cb6135e6fb70d4d2cec4ee9b"
+	if got := hex.EncodeToString(reposts[0].ClaimHash); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	want = "00000324e40fcb63a0b517a3660645e9bd99244a"
+	if got := hex.EncodeToString(reposts[0].ChannelHash); got != want {
+		t.Errorf("Expected channel %s, got %s", want, got)
+	}
+}
+
+func TestGetRepostChain(t *testing.T) {
+	filePath := "../testdata/V_chain.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	// D -> A -> B -> C -> A
+	claimHash, _ := hex.DecodeString("11158037afca9c2efabc3dff55e352bf1f5634c5")
+	chain, cycle, err := db.GetRepostChain(claimHash, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if !cycle || len(chain) != 3 {
+		t.Errorf("Expected a cycle after 3 claims, got %d claims, cycle %v", len(chain), cycle)
+	}
+
+	chain, cycle, err = db.GetRepostChain(claimHash, 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if cycle || len(chain) != 2 {
+		t.Errorf("Expected 2 claims without a cycle, got %d claims, cycle %v", len(chain), cycle)
+	}
+}
+
 func TestPrintChannelCount(t *testing.T) {
 	filePath := "../testdata/Z_resolve.csv"
 	CatCSV(filePath)
@@ -462,6 +531,77 @@ func TestGetClaimsInChannelCount(t *testing.T) {
 	}
 }
 
+func TestGetClaimsInChannel(t *testing.T) {
+	channelHash, _ := hex.DecodeString("c0ffee0000000000000000000000000000c0ffee")
+	filePath := "../testdata/JE_channel.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	tests := []struct {
+		orderBy string
+		reverse bool
+		offset  int
+		limit   int
+		want    []string
+	}{
+		// Name order is by name length first.
+		{dbpkg.ChannelOrderName, false, 0, 10, []string{"b", "aa", "ab"}},
+		{dbpkg.ChannelOrderName, false, 1, 1, []string{"aa"}},
+		{dbpkg.ChannelOrderName, true, 0, 10, []string{"ab", "aa", "b"}},
+		{dbpkg.ChannelOrderName, true, 2, 10, []string{"b"}},
+		{dbpkg.ChannelOrderHeight, false, 0, 10, []string{"aa", "ab", "b"}},
+		{dbpkg.ChannelOrderHeight, false, 1, 1, []string{"ab"}},
+		{dbpkg.ChannelOrderHeight, true, 0, 2, []string{"b", "ab"}},
+		{dbpkg.ChannelOrderHeight, false, 3, 10, []string{}},
+		// A zero limit only counts the claims.
+		{dbpkg.ChannelOrderHeight, false, 0, 0, []string{}},
+	}
+	for _, tt := range tests {
+		claims, total, err := db.GetClaimsInChannel(channelHash, tt.orderBy, tt.reverse, tt.offset, tt.limit)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		if total != 3 {
+			t.Errorf("%s reverse=%v offset=%d: expected 3 claims in total, got %d", tt.orderBy, tt.reverse, tt.offset, total)
+		}
+		names := make([]string, 0, len(claims))
+		for _, claim := range claims {
+			names = append(names, claim.Name)
+		}
+		if !reflect.DeepEqual(names, tt.want) {
+			t.Errorf("%s reverse=%v offset=%d limit=%d: expected %v, got %v",
+				tt.orderBy, tt.reverse, tt.offset, tt.limit, tt.want, names)
+		}
+	}
+
+	// The claims are resolved.
+	claims, _, err := db.GetClaimsInChannel(channelHash, dbpkg.ChannelOrderHeight, false, 0, 1)
+	if err != nil || len(claims) != 1 {
+		t.Fatalf("Expected 1 claim, got %v: %v", claims, err)
+	}
+	claim := claims[0]
+	if hex.EncodeToString(claim.ClaimHash) != "a000000000000000000000000000000000000001" ||
+		claim.TxNum != 10 || claim.Position != 0 || claim.Amount != 100000000 {
+		t.Errorf("Unexpected claim %#v", claim)
+	}
+	if hex.EncodeToString(claim.TxHash) != strings.Repeat("10", 32) {
+		t.Errorf("Expected the tx hash of tx 10, got %x", claim.TxHash)
+	}
+	if !bytes.Equal(claim.ChannelHash, channelHash) || !claim.IsControlling {
+		t.Errorf("Expected a controlling claim in the channel, got %#v", claim)
+	}
+
+	_, _, err = db.GetClaimsInChannel(channelHash, "amount", false, 0, 10)
+	if err == nil {
+		t.Error("Expected an error for an invalid order")
+	}
+}
+
 func TestPrintClaimShortId(t *testing.T) {
 	filePath := "../testdata/F_resolve.csv"
 	CatCSV(filePath)
@@ -667,6 +807,50 @@ func TestGetSupportAmount(t *testing.T) {
 	}
 }
 
+func TestGetSupports(t *testing.T) {
+	claimHashStr := "00000324e40fcb63a0b517a3660645e9bd99244a"
+	claimHash, err := hex.DecodeString(claimHashStr)
+	if err != nil {
+		t.Error(err)
+	}
+	filePath := "../testdata/K_resolve.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.Height = 0x40
+
+	// The second support activates at 0x64, so it's still pending.
+	supports, err := db.GetSupports(claimHash, false, 0, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(supports) != 1 {
+		t.Fatalf("Expected 1 support, got %d", len(supports))
+	}
+	if supports[0].Amount != 0x1312d00 || supports[0].ActivationHeight != 0x20 {
+		t.Errorf("Unexpected support %#v", supports[0])
+	}
+
+	supports, err = db.GetSupports(claimHash, true, 0, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(supports) != 2 {
+		t.Fatalf("Expected 2 supports, got %d", len(supports))
+	}
+
+	supports, err = db.GetSupports(claimHash, true, 1, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(supports) != 1 || supports[0].TxNum != 0x11 || supports[0].Position != 1 {
+		t.Errorf("Unexpected supports %#v", supports)
+	}
+}
+
 // TODO: verify where this hash comes from exactly.
 func TestGetTxHash(t *testing.T) {
 	txNum := uint32(0x6284e3)
@@ -711,6 +895,55 @@ func TestGetExpirationHeight(t *testing.T) {
 	}
 }
 
+func TestGetClaimExpirationHeight(t *testing.T) {
+	// Expired before the fork, keeps the original expiration time.
+	var lastUpdated uint32 = 100
+	want := lastUpdated + dbpkg.OriginalClaimExpirationTime
+	if got := dbpkg.GetClaimExpirationHeight(lastUpdated); got != want {
+		t.Errorf("Expected %d, got %d", want, got)
+	}
+
+	// Still live at the fork, gets the extended expiration time.
+	lastUpdated = dbpkg.ExtendedClaimExpirationForkHeight - dbpkg.OriginalClaimExpirationTime
+	want = lastUpdated + dbpkg.ExtendedClaimExpirationTime
+	if got := dbpkg.GetClaimExpirationHeight(lastUpdated); got != want {
+		t.Errorf("Expected %d, got %d", want, got)
+	}
+}
+
+func TestGetClaimsExpiring(t *testing.T) {
+	filePath := "../testdata/O.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	claims, err := db.GetClaimsExpiring(0x2230a7, 0x223246, 0, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(claims) != 4 {
+		t.Fatalf("Expected 4 claims, got %d", len(claims))
+	}
+	want := "ebf95f7fdb89db5467bb1b88ea3b0f0f7ee5ce36"
+	if got := hex.EncodeToString(claims[0].ClaimHash); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if claims[0].NormalizedName != "cnc" || claims[0].ExpirationHeight != 0x2230a7 {
+		t.Errorf("Unexpected claim %#v", claims[0])
+	}
+
+	claims, err = db.GetClaimsExpiring(0x2230a7, 0x223246, 3, 10)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(claims) != 1 || claims[0].ExpirationHeight != 0x223246 {
+		t.Errorf("Unexpected claims %#v", claims)
+	}
+}
+
 func TestGetActivation(t *testing.T) {
 	filePath := "../testdata/R_resolve.csv"
 	txNum := uint32(0x6284e3)
@@ -732,6 +965,96 @@ func TestGetActivation(t *testing.T) {
 	log.Printf("activation: %#v\n", activation)
 }
 
+func TestGetClaimsForName(t *testing.T) {
+	filePath := "../testdata/D_resolve.csv"
+	want := []string{
+		"00000324e40fcb63a0b517a3660645e9bd99244a",
+		"2556ed1cab9d17f2a9392030a9ad7f5d138f11bd",
+	}
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	claims, lastTakeoverHeight, err := db.GetClaimsForName("test")
+	if err != nil {
+		t.Error(err)
+	}
+	if lastTakeoverHeight != 0x20 {
+		t.Errorf("Expected last takeover height %d, got %d", 0x20, lastTakeoverHeight)
+	}
+	if len(claims) != len(want) {
+		t.Fatalf("Expected %d claims, got %d", len(want), len(claims))
+	}
+	for i, claim := range claims {
+		got := hex.EncodeToString(claim.ClaimHash)
+		if got != want[i] {
+			t.Errorf("Expected %s, got %s", want[i], got)
+		}
+		if claim.IsControlling != (i == 0) {
+			t.Errorf("Unexpected controlling flag for %s", got)
+		}
+	}
+	if claims[0].EffectiveAmount != 100000000 || claims[1].ActivationHeight != 0x30 {
+		t.Errorf("Unexpected claims %#v", claims)
+	}
+}
+
+func TestGetActivationDelay(t *testing.T) {
+	tests := []struct {
+		height             uint32
+		lastTakeoverHeight uint32
+		controlling        bool
+		want               uint32
+	}{
+		{1000, 0, false, 0},
+		{1000, 968, true, 1},
+		{1000, 0, true, 31},
+		{200000, 0, true, dbpkg.MaxTakeoverDelay},
+	}
+	for _, tt := range tests {
+		got := dbpkg.GetActivationDelay(tt.height, tt.lastTakeoverHeight, tt.controlling)
+		if got != tt.want {
+			t.Errorf("GetActivationDelay(%d, %d, %v) = %d, want %d", tt.height, tt.lastTakeoverHeight, tt.controlling, got, tt.want)
+		}
+	}
+}
+
+func TestGetTakeoverForecast(t *testing.T) {
+	filePath := "../testdata/Q_resolve.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.Height = 0x40
+
+	forecast, err := db.GetTakeoverForecast("test")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	// The other name's pending claim is skipped.
+	if len(forecast.Pending) != 2 {
+		t.Fatalf("Expected 2 pending activations, got %d", len(forecast.Pending))
+	}
+	if want := uint32(0x40 + 1 + (0x41-0x20)/dbpkg.ProportionalDelayFactor); forecast.NewClaimActivationHeight != want {
+		t.Errorf("Expected new claim activation at %d, got %d", want, forecast.NewClaimActivationHeight)
+	}
+	// The claim alone can't take over, the support pushes it past the
+	// controlling claim.
+	want := "2556ed1cab9d17f2a9392030a9ad7f5d138f11bd"
+	if got := hex.EncodeToString(forecast.TakeoverClaim); got != want {
+		t.Errorf("Expected takeover by %s, got %s", want, got)
+	}
+	if forecast.TakeoverHeight != 0x60 {
+		t.Errorf("Expected takeover at %d, got %d", 0x60, forecast.TakeoverHeight)
+	}
+}
+
 // TestPrintClaimToTXO Utility function to cat the ClaimToTXO csv.
 func TestPrintClaimToTXO(t *testing.T) {
 	filePath := "../testdata/E_resolve.csv"
@@ -878,3 +1201,418 @@ func TestIter(t *testing.T) {
 		i++
 	}
 }
+
+func TestGetClaimDiffs(t *testing.T) {
+	filePath := "../testdata/Y_diff.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.Height = 103
+
+	diffs, err := db.GetClaimDiffs(100, 110)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(diffs) != 4 {
+		t.Fatalf("Expected 4 diffs, got %d", len(diffs))
+	}
+	if diffs[0].Height != 100 || len(diffs[0].TouchedClaims) != 0 || len(diffs[0].DeletedClaims) != 0 {
+		t.Errorf("Unexpected diff %#v", diffs[0])
+	}
+	if len(diffs[1].TouchedClaims) != 2 || len(diffs[1].DeletedClaims) != 1 {
+		t.Errorf("Unexpected diff %#v", diffs[1])
+	}
+	want := strings.Repeat("cc", 20)
+	if got := hex.EncodeToString(diffs[1].DeletedClaims[0]); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	want = strings.Repeat("66", 32)
+	if got := hex.EncodeToString(diffs[2].BlockHash); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if len(diffs[3].TouchedClaims) != 1 {
+		t.Errorf("Unexpected diff %#v", diffs[3])
+	}
+
+	// The diff of block 99 isn't kept, it isn't taken as empty.
+	if diffs, err = db.GetClaimDiffs(99, 110); err == nil {
+		t.Errorf("Expected an error for a block without a diff, got %#v", diffs)
+	}
+
+	diffs, err = db.GetClaimDiffs(104, 110)
+	if err != nil {
+		t.Error(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("Expected no diffs past the tip, got %d", len(diffs))
+	}
+}
+
+func TestGetOutpointClaim(t *testing.T) {
+	filePath := "../testdata/NGEL_outpoint.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	claimId := strings.Repeat("aa", 20)
+
+	txHash, _ := chainhash.NewHash(bytes.Repeat([]byte{0x11}, 32))
+	claim, err := db.GetOutpointClaim(txHash, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim == nil || hex.EncodeToString(claim.ClaimHash) != claimId {
+		t.Fatalf("Unexpected claim %#v", claim)
+	}
+	if claim.Type != dbpkg.TXOTypeClaim || !claim.Current || claim.TxNum != 10 {
+		t.Errorf("Unexpected claim %#v", claim)
+	}
+
+	txHash, _ = chainhash.NewHash(bytes.Repeat([]byte{0x22}, 32))
+	claim, err = db.GetOutpointClaim(txHash, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claim == nil || hex.EncodeToString(claim.ClaimHash) != claimId || claim.Type != dbpkg.TXOTypeSupport {
+		t.Errorf("Unexpected support %#v", claim)
+	}
+
+	txHash, _ = chainhash.NewHash(bytes.Repeat([]byte{0x33}, 32))
+	claim, err = db.GetOutpointClaim(txHash, 0)
+	if err != nil {
+		t.Error(err)
+	}
+	if claim != nil {
+		t.Errorf("Expected no claim for an unknown tx, got %#v", claim)
+	}
+}
+
+func TestGetAddressClaims(t *testing.T) {
+	filePath := "../testdata/uGEL_addressclaims.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.AddressClaimsCache = dbpkg.NewAddressClaimsCache()
+	hashX := bytes.Repeat([]byte{0xbb}, 11)
+	claimId := strings.Repeat("aa", 20)
+
+	// The third UTXO is neither a claim nor a support.
+	claims, err := db.GetAddressClaims(hashX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 2 {
+		t.Fatalf("Expected 2 claims, got %#v", claims)
+	}
+	if claims[0].TxNum != 10 || claims[0].Type != dbpkg.TXOTypeClaim || hex.EncodeToString(claims[0].ClaimHash) != claimId {
+		t.Errorf("Unexpected claim %#v", claims[0])
+	}
+	if claims[1].TxNum != 11 || claims[1].TxPos != 1 || claims[1].Type != dbpkg.TXOTypeSupport {
+		t.Errorf("Unexpected support %#v", claims[1])
+	}
+
+	// The claims are cached until the next block.
+	cached, err := db.GetAddressClaims(hashX)
+	if err != nil || len(cached) != 2 || &cached[0] != &claims[0] {
+		t.Errorf("Expected the cached claims, got %#v: %v", cached, err)
+	}
+}
+
+func TestGetClaimsByShortId(t *testing.T) {
+	filePath := "../testdata/FG_shortid.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	first := "ab12" + strings.Repeat("00", 18)
+	second := "ab34" + strings.Repeat("00", 18)
+
+	claims, err := db.GetClaimsByShortId("foo", "ab", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 2 {
+		t.Fatalf("Expected 2 claims, got %d", len(claims))
+	}
+	if hex.EncodeToString(claims[0].ClaimHash) != first || hex.EncodeToString(claims[1].ClaimHash) != second {
+		t.Errorf("Unexpected claims %#v", claims)
+	}
+	// The oldest claim gets the shortest url.
+	if claims[0].ShortUrl != "Foo#a" || claims[1].ShortUrl != "Foo#ab3" {
+		t.Errorf("Unexpected short urls %s and %s", claims[0].ShortUrl, claims[1].ShortUrl)
+	}
+	if claims[0].TxNum != 20 || claims[0].Name != "Foo" {
+		t.Errorf("Unexpected claim %#v", claims[0])
+	}
+
+	claims, err = db.GetClaimsByShortId("foo", "ab", 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || hex.EncodeToString(claims[0].ClaimHash) != second {
+		t.Errorf("Unexpected claims %#v", claims)
+	}
+
+	// Prefixes longer than the indexed short ids are matched in full.
+	claims, err = db.GetClaimsByShortId("foo", first[:16]+"01", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 0 {
+		t.Errorf("Expected no claims, got %#v", claims)
+	}
+	claims, err = db.GetClaimsByShortId("foo", first, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims) != 1 || hex.EncodeToString(claims[0].ClaimHash) != first {
+		t.Errorf("Unexpected claims %#v", claims)
+	}
+}
+
+func TestGetNamesByPrefix(t *testing.T) {
+	filePath := "../testdata/P_names.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	names, err := db.GetNamesByPrefix("ab", "", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 4 || names[1].NormalizedName != "abc" ||
+		names[1].LastTakeoverHeight != 102 || hex.EncodeToString(names[1].ClaimHash) != strings.Repeat("03", 20) {
+		t.Errorf("Unexpected names %#v", names)
+	}
+
+	tests := []struct {
+		prefix string
+		after  string
+		limit  int
+		want   []string
+	}{
+		{"ab", "", 10, []string{"ab", "abc", "abcdef", "abd"}},
+		{"ab", "abc", 1, []string{"abcdef"}},
+		{"ab", "abcdef", 10, []string{"abd"}},
+		{"ab", "abd", 10, []string{}},
+		{"", "", 3, []string{"a", "ab", "abc"}},
+		{"", "abc", 10, []string{"abcdef", "abd", "b", "ba"}},
+		{"b", "", 10, []string{"b", "ba"}},
+		// A cursor before the prefix starts at the prefix.
+		{"b", "a", 1, []string{"b"}},
+		{"c", "", 10, []string{}},
+	}
+	for _, tt := range tests {
+		names, err := db.GetNamesByPrefix(tt.prefix, tt.after, tt.limit)
+		if err != nil {
+			t.Error(err)
+			continue
+		}
+		got := make([]string, 0, len(names))
+		for _, name := range names {
+			got = append(got, name.NormalizedName)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("prefix %q after %q: expected %v, got %v", tt.prefix, tt.after, tt.want, got)
+		}
+	}
+}
+
+func TestGetChannelStats(t *testing.T) {
+	filePath := "../testdata/ZJ_stats.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db.ChannelStatsCache = dbpkg.NewChannelStatsCache()
+	channelHash, _ := hex.DecodeString(strings.Repeat("ee", 20))
+
+	stats, err := db.GetChannelStats(channelHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := dbpkg.ChannelStats{
+		ClaimCount:      2,
+		SupportAmount:   150,
+		EffectiveAmount: 500,
+		RepostCount:     3,
+		EarliestHeight:  0,
+		LatestHeight:    1,
+	}
+	if *stats != want {
+		t.Errorf("Expected %#v, got %#v", want, *stats)
+	}
+	// The stats are cached until the next block.
+	if cached, err := db.GetChannelStats(channelHash); err != nil || cached != stats {
+		t.Errorf("Expected the cached stats, got %#v: %v", cached, err)
+	}
+
+	// The stats are updated from the claim diff of block 1, which signed
+	// 3333 with the channel and abandoned 2222. The channel's rows are left
+	// as they were, so only the diff moves the claims.
+	db.Height = 1
+	stats, err = db.GetChannelStats(channelHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = dbpkg.ChannelStats{
+		ClaimCount:      2,
+		SupportAmount:   110,
+		EffectiveAmount: 700,
+		RepostCount:     3,
+		EarliestHeight:  0,
+		LatestHeight:    2,
+	}
+	if *stats != want {
+		t.Errorf("Expected %#v, got %#v", want, *stats)
+	}
+
+	otherHash, _ := hex.DecodeString(strings.Repeat("dd", 20))
+	stats, err = db.GetChannelStats(otherHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *stats != (dbpkg.ChannelStats{}) {
+		t.Errorf("Expected empty stats, got %#v", *stats)
+	}
+}
+
+func TestGetTopStakedClaims(t *testing.T) {
+	filePath := "../testdata/iE_staked.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	claimIds := func(claims []dbpkg.StakedClaim) string {
+		ids := make([]string, 0, len(claims))
+		for _, claim := range claims {
+			ids = append(ids, hex.EncodeToString(claim.ClaimHash[:1]))
+		}
+		return strings.Join(ids, ",")
+	}
+
+	// 44 was abandoned and 55 has no stake.
+	claims, err := db.GetTopStakedClaims("", 0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := claimIds(claims); got != "22,33,11" {
+		t.Errorf("Expected 22,33,11, got %s", got)
+	}
+	if claims[0].EffectiveAmount != 900 {
+		t.Errorf("Unexpected amount %d", claims[0].EffectiveAmount)
+	}
+
+	claims, err = db.GetTopStakedClaims("", 0, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := claimIds(claims); got != "33" {
+		t.Errorf("Expected 33, got %s", got)
+	}
+
+	claims, err = db.GetTopStakedClaims("", 2, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := claimIds(claims); got != "33" {
+		t.Errorf("Expected 33, got %s", got)
+	}
+
+	// With a cache the ranking is only read, and its claims carry their
+	// height and type. The claims have no txs, so they have no type.
+	db.TopStaked = dbpkg.NewTopStakedCache()
+	if claims, err = db.GetTopStakedClaims("", 0, 0, 10); err != nil || len(claims) != 0 {
+		t.Errorf("Expected no claims before ranking, got %v: %v", claims, err)
+	}
+	if err := db.InitTopStaked(3); err != nil {
+		t.Fatal(err)
+	}
+	claims, err = db.GetTopStakedClaims("", 0, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := claimIds(claims); got != "22,33,11" {
+		t.Errorf("Expected 22,33,11, got %s", got)
+	}
+	if claims[1].Height < 2 || claims[1].ClaimType != "" {
+		t.Errorf("Unexpected claim %#v", claims[1])
+	}
+	if claims, err = db.GetTopStakedClaims(dbpkg.ClaimTypeStream, 0, 0, 10); err != nil || len(claims) != 0 {
+		t.Errorf("Expected no streams, got %v: %v", claims, err)
+	}
+}
+
+func TestInitStats(t *testing.T) {
+	filePath := "../testdata/ELPi_stats.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err := db.InitStats(10); err != nil {
+		t.Fatal(err)
+	}
+	want := dbpkg.ClaimtrieStats{Height: 10, Claims: 2, Supports: 3, ActiveNames: 1, TotalStaked: 350}
+	if got := db.GetStats(); got != want {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestGetSpender(t *testing.T) {
+	filePath := "../testdata/xXB_spender.csv"
+	db, _, err := OpenAndFillTmpDBColumnFamlies(filePath)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	hashX := bytes.Repeat([]byte{0xaa}, 11)
+	fundingHash, _ := chainhash.NewHash(bytes.Repeat([]byte{0x11}, 32))
+	outpoint := wire.NewOutPoint(fundingHash, 1)
+
+	spender, height, err := db.GetSpender(outpoint, hashX, 4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "26c40d128f4f32880072b08f278ff29cf73eef783924e041b7d177015ceff6ec"
+	if spender == nil || spender.String() != want || height != 5 {
+		t.Errorf("Expected %s at 5, got %v at %d", want, spender, height)
+	}
+
+	// Another output of the same tx is unspent.
+	spender, _, err = db.GetSpender(wire.NewOutPoint(fundingHash, 0), hashX, 4, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spender != nil {
+		t.Errorf("Expected no spender, got %v", spender)
+	}
+
+	// The walk stops after maxTxs txs.
+	spender, _, err = db.GetSpender(outpoint, hashX, 4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spender != nil {
+		t.Errorf("Expected no spender within 0 txs, got %v", spender)
+	}
+}
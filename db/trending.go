@@ -0,0 +1,373 @@
+package db
+
+// trending.go contains the fast_ar trending algorithm, computed from the
+// TrendingNotifications written for each block.
+
+import (
+	"container/heap"
+	"math"
+	"sync"
+
+	"github.com/lbryio/herald.go/db/prefixes"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// TrendingTimescale is the e-folding time of trending spikes in blocks.
+	TrendingTimescale = 576.0
+	// TrendingSoftenPower softens large changes in amount.
+	TrendingSoftenPower = 1.0 / 3.0
+	// Coin is the number of dewies in an LBC.
+	Coin = 1e8
+	// TrendingMinScore is the smallest score, decayed to the tracked
+	// height, that is kept.
+	TrendingMinScore = 1e-6
+	// TrendingWindow is the number of blocks replayed by InitTrending.
+	// Spikes older than that have decayed by exp(-20), below
+	// TrendingMinScore for any realistic amount.
+	TrendingWindow = 20 * TrendingTimescale
+)
+
+// Trending scores are kept "squashed", squash(x) = sign(x)*log(1+|x|), so
+// that spikes inflated by exp(height/TrendingTimescale) never overflow.
+// Later spikes weigh exponentially more, which is the same as decaying
+// every earlier one, without touching every claim on each block.
+
+func logsumexp(x, y float64) float64 {
+	if x > y {
+		return x + math.Log1p(math.Exp(y-x))
+	}
+	return y + math.Log1p(math.Exp(x-y))
+}
+
+func logdiffexp(big, small float64) float64 {
+	return big + math.Log1p(-math.Exp(small-big))
+}
+
+func squash(x float64) float64 {
+	if x < 0.0 {
+		return -math.Log(1.0 - x)
+	}
+	return math.Log(x + 1.0)
+}
+
+func logToSquash(x float64) float64 {
+	return logsumexp(x, 0.0)
+}
+
+func squashToLog(x float64) float64 {
+	return logdiffexp(x, 0.0)
+}
+
+// squashedAdd returns squash(unsquash(x) + unsquash(y)) without overflow.
+func squashedAdd(x, y float64) float64 {
+	switch {
+	case x == 0.0:
+		return y
+	case y == 0.0:
+		return x
+	case x < 0.0 && y < 0.0:
+		return -squashedAdd(-x, -y)
+	case x > 0.0 && y > 0.0:
+		return logToSquash(logsumexp(squashToLog(x), squashToLog(y)))
+	case x < 0.0:
+		return squashedAdd(y, x)
+	case x >= -y:
+		return logToSquash(logdiffexp(squashToLog(x), squashToLog(-y)))
+	default:
+		return -logToSquash(logdiffexp(squashToLog(-y), squashToLog(x)))
+	}
+}
+
+// squashedMultiply returns squash(unsquash(x) * exp(y)) without overflow.
+func squashedMultiply(x, y float64) float64 {
+	if x == 0.0 {
+		return 0.0
+	}
+	if x < 0.0 {
+		return -logToSquash(squashToLog(-x) + y)
+	}
+	return logToSquash(squashToLog(x) + y)
+}
+
+func softenLBC(lbc float64) float64 {
+	return math.Pow(lbc, TrendingSoftenPower)
+}
+
+func spikePower(newAmount float64) float64 {
+	if newAmount < 50.0 {
+		return 0.5
+	} else if newAmount < 85.0 {
+		return newAmount / 100.0
+	}
+	return 0.85
+}
+
+// spikeMass is the size of the spike for a change in a claim's amount from
+// oldAmount to newAmount, in LBC.
+func spikeMass(oldAmount, newAmount float64) float64 {
+	softenedChange := softenLBC(math.Abs(newAmount - oldAmount))
+	changeInSoftened := math.Abs(softenLBC(newAmount) - softenLBC(oldAmount))
+	power := spikePower(newAmount)
+	mass := math.Pow(changeInSoftened, power) * math.Pow(softenedChange, 1.0-power)
+	if oldAmount > newAmount {
+		return -mass
+	}
+	return mass
+}
+
+// TrendingSpike returns the squashed spike for a claim whose amount changed
+// from prevAmount to newAmount dewies at height.
+func TrendingSpike(height uint32, prevAmount, newAmount uint64) float64 {
+	mass := spikeMass(float64(prevAmount)/Coin, float64(newAmount)/Coin)
+	return squashedMultiply(squash(mass), float64(height)/TrendingTimescale)
+}
+
+// TrendingScores holds the fast_ar trending score of each claim.
+type TrendingScores struct {
+	mu     sync.RWMutex
+	scores map[string]float64
+	// spikes are the spikes added at each of the last ReorgLimit heights,
+	// so a disconnected block can be unwound.
+	spikes map[uint32][]TrendingClaim
+	// incomplete is set when a block was unwound without its spikes.
+	incomplete bool
+	// height is the last tracked height, scores are pruned once per height.
+	height uint32
+	pruned bool
+}
+
+// NewTrendingScores returns empty trending scores.
+func NewTrendingScores() *TrendingScores {
+	return &TrendingScores{
+		scores: make(map[string]float64),
+		spikes: make(map[uint32][]TrendingClaim),
+	}
+}
+
+func (t *TrendingScores) addLocked(claimHash []byte, spike float64) {
+	score := squashedAdd(t.scores[string(claimHash)], spike)
+	if score == 0.0 {
+		delete(t.scores, string(claimHash))
+	} else {
+		t.scores[string(claimHash)] = score
+	}
+}
+
+// Add adds a spike to the score of claimHash.
+func (t *TrendingScores) Add(claimHash []byte, spike float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addLocked(claimHash, spike)
+}
+
+// Track starts recording the spikes added at height, and forgets those of
+// heights ReorgLimit or more blocks below it.
+func (t *TrendingScores) Track(height uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.spikes[height]; !ok {
+		t.spikes[height] = nil
+	}
+	if height != t.height {
+		t.height = height
+		t.pruned = false
+	}
+	for h := range t.spikes {
+		if h+ReorgLimit <= height {
+			delete(t.spikes, h)
+		}
+	}
+}
+
+// AddAt adds a spike to the score of claimHash, recording it if height is
+// tracked.
+func (t *TrendingScores) AddAt(height uint32, claimHash []byte, spike float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.addLocked(claimHash, spike)
+	if spikes, ok := t.spikes[height]; ok {
+		t.spikes[height] = append(spikes, TrendingClaim{ClaimHash: claimHash, Score: spike})
+	}
+}
+
+// Unwind removes the spikes added at height. If they were not recorded
+// the scores are marked incomplete.
+func (t *TrendingScores) Unwind(height uint32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spikes, ok := t.spikes[height]
+	if !ok {
+		t.incomplete = true
+		return
+	}
+	for i := len(spikes) - 1; i >= 0; i-- {
+		t.addLocked(spikes[i].ClaimHash, -spikes[i].Score)
+	}
+	delete(t.spikes, height)
+}
+
+// Incomplete reports whether a block was unwound without its spikes. It is
+// safe to call on nil.
+func (t *TrendingScores) Incomplete() bool {
+	if t == nil {
+		return false
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.incomplete
+}
+
+// Get returns the score of claimHash. It is safe to call on nil.
+func (t *TrendingScores) Get(claimHash []byte) float64 {
+	if t == nil {
+		return 0.0
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.scores[string(claimHash)]
+}
+
+// TrendingClaim is a claim hash and its trending score.
+type TrendingClaim struct {
+	ClaimHash []byte
+	Score     float64
+}
+
+type trendingHeap []TrendingClaim
+
+func (h trendingHeap) Len() int            { return len(h) }
+func (h trendingHeap) Less(i, j int) bool  { return h[i].Score < h[j].Score }
+func (h trendingHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *trendingHeap) Push(x interface{}) { *h = append(*h, x.(TrendingClaim)) }
+func (h *trendingHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// prune forgets the scores which have decayed below TrendingMinScore at
+// the tracked height. It only sweeps the scores once per height.
+func (t *TrendingScores) prune() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.pruned {
+		return
+	}
+	minScore := math.Log(TrendingMinScore) + float64(t.height)/TrendingTimescale
+	for claimHash, score := range t.scores {
+		if squashToLog(math.Abs(score)) < minScore {
+			delete(t.scores, claimHash)
+		}
+	}
+	t.pruned = true
+}
+
+// Top returns the n highest scoring claims, highest first. Scores which
+// have decayed below TrendingMinScore are pruned first.
+func (t *TrendingScores) Top(n int) []TrendingClaim {
+	if t == nil || n <= 0 {
+		return nil
+	}
+	t.prune()
+	t.mu.RLock()
+	h := make(trendingHeap, 0, n+1)
+	for claimHash, score := range t.scores {
+		if len(h) == n && score <= h[0].Score {
+			continue
+		}
+		heap.Push(&h, TrendingClaim{ClaimHash: []byte(claimHash), Score: score})
+		if len(h) > n {
+			heap.Pop(&h)
+		}
+	}
+	t.mu.RUnlock()
+	top := make([]TrendingClaim, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		top[i] = heap.Pop(&h).(TrendingClaim)
+	}
+	return top
+}
+
+// trendingNotifications applies the TrendingNotifications for heights
+// fromHeight through toHeight to scores, recording the spikes of the last
+// ReorgLimit heights.
+func (db *ReadOnlyDBColumnFamily) trendingNotifications(scores *TrendingScores, fromHeight, toHeight uint32) error {
+	tracked := fromHeight
+	if toHeight >= ReorgLimit && toHeight-ReorgLimit+1 > tracked {
+		tracked = toHeight - ReorgLimit + 1
+	}
+	for height := tracked; height <= toHeight; height++ {
+		scores.Track(height)
+	}
+
+	handle, err := db.EnsureHandle(prefixes.TrendingNotifications)
+	if err != nil {
+		return err
+	}
+	key := &prefixes.TrendingNotificationKey{
+		Prefix: []byte{prefixes.TrendingNotifications},
+		Height: fromHeight,
+	}
+	options := NewIterateOptions().WithDB(db).WithCfHandle(handle).WithPrefix([]byte{prefixes.TrendingNotifications})
+	options = options.WithStart(key.PartialPack(1)).WithIncludeValue(true)
+	defer options.Grp.Stop()
+	for kv := range IterCF(db.DB, options) {
+		notificationKey := kv.Key.(*prefixes.TrendingNotificationKey)
+		if notificationKey.Height > toHeight {
+			break
+		}
+		notification := kv.Value.(*prefixes.TrendingNotificationValue)
+		scores.AddAt(notificationKey.Height, notificationKey.ClaimHash,
+			TrendingSpike(notificationKey.Height, notification.PreviousAmount, notification.NewAmount))
+	}
+	return nil
+}
+
+// InitTrending computes the trending scores from the TrendingNotifications
+// of the last TrendingWindow blocks up to height.
+func (db *ReadOnlyDBColumnFamily) InitTrending(height uint32) error {
+	scores := NewTrendingScores()
+	var fromHeight uint32
+	if height > TrendingWindow {
+		fromHeight = height - TrendingWindow
+	}
+	if err := db.trendingNotifications(scores, fromHeight, height); err != nil {
+		return err
+	}
+	if db.Trending == nil {
+		db.Trending = scores
+	} else {
+		db.Trending.mu.Lock()
+		db.Trending.scores = scores.scores
+		db.Trending.spikes = scores.spikes
+		db.Trending.incomplete = false
+		db.Trending.height = scores.height
+		db.Trending.pruned = false
+		db.Trending.mu.Unlock()
+	}
+	log.Infof("trending scores for %d claims at height %d", len(scores.scores), height)
+	return nil
+}
+
+// advanceTrending applies the TrendingNotifications of a new block.
+func (db *ReadOnlyDBColumnFamily) advanceTrending(height uint32) error {
+	if db.Trending == nil {
+		return nil
+	}
+	return db.trendingNotifications(db.Trending, height, height)
+}
+
+// unwindTrending removes the spikes of a disconnected block.
+func (db *ReadOnlyDBColumnFamily) unwindTrending(height uint32) {
+	if db.Trending == nil {
+		return
+	}
+	db.Trending.Unwind(height)
+}
+
+// GetTrendingScore returns the trending score of a claim.
+func (db *ReadOnlyDBColumnFamily) GetTrendingScore(claimHash []byte) float64 {
+	return db.Trending.Get(claimHash)
+}
@@ -0,0 +1,90 @@
+package db_test
+
+import (
+	"math"
+	"testing"
+
+	dbpkg "github.com/lbryio/herald.go/db"
+)
+
+func TestTrendingSpike(t *testing.T) {
+	up := dbpkg.TrendingSpike(1000, 0, 100*dbpkg.Coin)
+	down := dbpkg.TrendingSpike(1000, 100*dbpkg.Coin, 0)
+	if up <= 0 || down >= 0 {
+		t.Errorf("Expected a positive and a negative spike, got %v and %v", up, down)
+	}
+	later := dbpkg.TrendingSpike(2000, 0, 100*dbpkg.Coin)
+	if later <= up {
+		t.Errorf("Expected a later spike to weigh more, got %v <= %v", later, up)
+	}
+	if spike := dbpkg.TrendingSpike(1000, 5, 5); spike != 0 {
+		t.Errorf("Expected no spike without a change, got %v", spike)
+	}
+}
+
+func TestTrendingScores(t *testing.T) {
+	a, b, c := []byte("a"), []byte("b"), []byte("c")
+	scores := dbpkg.NewTrendingScores()
+	scores.Add(a, dbpkg.TrendingSpike(1000, 0, 10*dbpkg.Coin))
+	scores.Add(b, dbpkg.TrendingSpike(1000, 0, 1000*dbpkg.Coin))
+	scores.Add(c, dbpkg.TrendingSpike(1000, 0, 100*dbpkg.Coin))
+
+	top := scores.Top(2)
+	if len(top) != 2 || string(top[0].ClaimHash) != "b" || string(top[1].ClaimHash) != "c" {
+		t.Errorf("Unexpected top claims %v", top)
+	}
+
+	// Abandoning the support cancels the spike.
+	scores.Add(b, dbpkg.TrendingSpike(1000, 1000*dbpkg.Coin, 0))
+	if score := scores.Get(b); score != 0 {
+		t.Errorf("Expected a zero score, got %v", score)
+	}
+	top = scores.Top(10)
+	if len(top) != 2 || string(top[0].ClaimHash) != "c" {
+		t.Errorf("Unexpected top claims %v", top)
+	}
+
+	// Scores which have decayed to nothing are pruned.
+	scores.Track(1000 + 20*uint32(dbpkg.TrendingTimescale))
+	if top = scores.Top(10); len(top) != 0 {
+		t.Errorf("Expected decayed scores to be pruned, got %v", top)
+	}
+
+	var nilScores *dbpkg.TrendingScores
+	if nilScores.Get(a) != 0 || nilScores.Top(1) != nil {
+		t.Error("Expected nil scores to be empty")
+	}
+}
+
+func TestTrendingUnwind(t *testing.T) {
+	a, b := []byte("a"), []byte("b")
+	scores := dbpkg.NewTrendingScores()
+	scores.Track(1000)
+	scores.AddAt(1000, a, dbpkg.TrendingSpike(1000, 0, 10*dbpkg.Coin))
+	scores.Track(1001)
+	scores.AddAt(1001, a, dbpkg.TrendingSpike(1001, 10*dbpkg.Coin, 50*dbpkg.Coin))
+	scores.AddAt(1001, b, dbpkg.TrendingSpike(1001, 0, 20*dbpkg.Coin))
+	before := scores.Get(a)
+
+	scores.Track(1002)
+	scores.AddAt(1002, a, dbpkg.TrendingSpike(1002, 50*dbpkg.Coin, 0))
+	scores.Unwind(1002)
+	if score := scores.Get(a); math.Abs(score-before) > 1e-9 {
+		t.Errorf("Expected %v after unwinding, got %v", before, score)
+	}
+
+	scores.Unwind(1001)
+	if score := scores.Get(b); score != 0 {
+		t.Errorf("Expected b to be unwound, got %v", score)
+	}
+	if scores.Incomplete() {
+		t.Error("Expected complete scores")
+	}
+
+	// Heights ReorgLimit blocks below the tip are forgotten.
+	scores.Track(1000 + dbpkg.ReorgLimit)
+	scores.Unwind(1000)
+	if !scores.Incomplete() {
+		t.Error("Expected incomplete scores")
+	}
+}
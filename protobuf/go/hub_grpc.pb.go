@@ -33,6 +33,12 @@ type HubClient interface {
 	Height(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*UInt32Value, error)
 	HeightSubscribe(ctx context.Context, in *UInt32Value, opts ...grpc.CallOption) (Hub_HeightSubscribeClient, error)
 	Resolve(ctx context.Context, in *StringArray, opts ...grpc.CallOption) (*Outputs, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	ListClaims(ctx context.Context, in *ListClaimsRequest, opts ...grpc.CallOption) (*Outputs, error)
+	ChannelClaims(ctx context.Context, in *ChannelClaimsRequest, opts ...grpc.CallOption) (*Outputs, error)
+	Trending(ctx context.Context, in *UInt32Value, opts ...grpc.CallOption) (*Outputs, error)
+	ClaimDiffs(ctx context.Context, in *ClaimDiffRequest, opts ...grpc.CallOption) (Hub_ClaimDiffsClient, error)
+	Stats(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*StatsResponse, error)
 }
 
 type hubClient struct {
@@ -165,6 +171,83 @@ func (c *hubClient) Resolve(ctx context.Context, in *StringArray, opts ...grpc.C
 	return out, nil
 }
 
+func (c *hubClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, "/pb.Hub/History", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) ListClaims(ctx context.Context, in *ListClaimsRequest, opts ...grpc.CallOption) (*Outputs, error) {
+	out := new(Outputs)
+	err := c.cc.Invoke(ctx, "/pb.Hub/ListClaims", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) ChannelClaims(ctx context.Context, in *ChannelClaimsRequest, opts ...grpc.CallOption) (*Outputs, error) {
+	out := new(Outputs)
+	err := c.cc.Invoke(ctx, "/pb.Hub/ChannelClaims", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) Trending(ctx context.Context, in *UInt32Value, opts ...grpc.CallOption) (*Outputs, error) {
+	out := new(Outputs)
+	err := c.cc.Invoke(ctx, "/pb.Hub/Trending", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *hubClient) ClaimDiffs(ctx context.Context, in *ClaimDiffRequest, opts ...grpc.CallOption) (Hub_ClaimDiffsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Hub_ServiceDesc.Streams[1], "/pb.Hub/ClaimDiffs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &hubClaimDiffsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Hub_ClaimDiffsClient interface {
+	Recv() (*ClaimDiff, error)
+	grpc.ClientStream
+}
+
+type hubClaimDiffsClient struct {
+	grpc.ClientStream
+}
+
+func (x *hubClaimDiffsClient) Recv() (*ClaimDiff, error) {
+	m := new(ClaimDiff)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *hubClient) Stats(ctx context.Context, in *EmptyMessage, opts ...grpc.CallOption) (*StatsResponse, error) {
+	out := new(StatsResponse)
+	err := c.cc.Invoke(ctx, "/pb.Hub/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HubServer is the server API for Hub service.
 // All implementations must embed UnimplementedHubServer
 // for forward compatibility
@@ -180,6 +263,12 @@ type HubServer interface {
 	Height(context.Context, *EmptyMessage) (*UInt32Value, error)
 	HeightSubscribe(*UInt32Value, Hub_HeightSubscribeServer) error
 	Resolve(context.Context, *StringArray) (*Outputs, error)
+	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	ListClaims(context.Context, *ListClaimsRequest) (*Outputs, error)
+	ChannelClaims(context.Context, *ChannelClaimsRequest) (*Outputs, error)
+	Trending(context.Context, *UInt32Value) (*Outputs, error)
+	ClaimDiffs(*ClaimDiffRequest, Hub_ClaimDiffsServer) error
+	Stats(context.Context, *EmptyMessage) (*StatsResponse, error)
 	mustEmbedUnimplementedHubServer()
 }
 
@@ -220,6 +309,24 @@ func (UnimplementedHubServer) HeightSubscribe(*UInt32Value, Hub_HeightSubscribeS
 func (UnimplementedHubServer) Resolve(context.Context, *StringArray) (*Outputs, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Resolve not implemented")
 }
+func (UnimplementedHubServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
+}
+func (UnimplementedHubServer) ListClaims(context.Context, *ListClaimsRequest) (*Outputs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClaims not implemented")
+}
+func (UnimplementedHubServer) ChannelClaims(context.Context, *ChannelClaimsRequest) (*Outputs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChannelClaims not implemented")
+}
+func (UnimplementedHubServer) Trending(context.Context, *UInt32Value) (*Outputs, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Trending not implemented")
+}
+func (UnimplementedHubServer) ClaimDiffs(*ClaimDiffRequest, Hub_ClaimDiffsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ClaimDiffs not implemented")
+}
+func (UnimplementedHubServer) Stats(context.Context, *EmptyMessage) (*StatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 func (UnimplementedHubServer) mustEmbedUnimplementedHubServer() {}
 
 // UnsafeHubServer may be embedded to opt out of forward compatibility for this service.
@@ -434,6 +541,117 @@ func _Hub_Resolve_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Hub_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Hub/History",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ListClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ListClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Hub/ListClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ListClaims(ctx, req.(*ListClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ChannelClaims_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelClaimsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).ChannelClaims(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Hub/ChannelClaims",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).ChannelClaims(ctx, req.(*ChannelClaimsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_Trending_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UInt32Value)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).Trending(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Hub/Trending",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).Trending(ctx, req.(*UInt32Value))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Hub_ClaimDiffs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ClaimDiffRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(HubServer).ClaimDiffs(m, &hubClaimDiffsServer{stream})
+}
+
+type Hub_ClaimDiffsServer interface {
+	Send(*ClaimDiff) error
+	grpc.ServerStream
+}
+
+type hubClaimDiffsServer struct {
+	grpc.ServerStream
+}
+
+func (x *hubClaimDiffsServer) Send(m *ClaimDiff) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Hub_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmptyMessage)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HubServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Hub/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HubServer).Stats(ctx, req.(*EmptyMessage))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Hub_ServiceDesc is the grpc.ServiceDesc for Hub service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -481,6 +699,26 @@ var Hub_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Resolve",
 			Handler:    _Hub_Resolve_Handler,
 		},
+		{
+			MethodName: "History",
+			Handler:    _Hub_History_Handler,
+		},
+		{
+			MethodName: "ListClaims",
+			Handler:    _Hub_ListClaims_Handler,
+		},
+		{
+			MethodName: "ChannelClaims",
+			Handler:    _Hub_ChannelClaims_Handler,
+		},
+		{
+			MethodName: "Trending",
+			Handler:    _Hub_Trending_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Hub_Stats_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -488,6 +726,11 @@ var Hub_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Hub_HeightSubscribe_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ClaimDiffs",
+			Handler:       _Hub_ClaimDiffs_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "hub.proto",
 }
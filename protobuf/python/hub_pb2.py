@@ -15,7 +15,7 @@ _sym_db = _symbol_database.Default()
 import result_pb2 as result__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\thub.proto\x12\x02pb\x1a\x0cresult.proto\"\x0e\n\x0c\x45mptyMessage\".\n\rServerMessage\x12\x0f\n\x07\x61\x64\x64ress\x18\x01 \x01(\t\x12\x0c\n\x04port\x18\x02 \x01(\t\"N\n\x0cHelloMessage\x12\x0c\n\x04port\x18\x01 \x01(\t\x12\x0c\n\x04host\x18\x02 \x01(\t\x12\"\n\x07servers\x18\x03 \x03(\x0b\x32\x11.pb.ServerMessage\"0\n\x0fInvertibleField\x12\x0e\n\x06invert\x18\x01 \x01(\x08\x12\r\n\x05value\x18\x02 \x03(\t\"\x1c\n\x0bStringValue\x12\r\n\x05value\x18\x01 \x01(\t\"\x1c\n\x0bStringArray\x12\r\n\x05value\x18\x01 \x03(\t\"\x1a\n\tBoolValue\x12\r\n\x05value\x18\x01 \x01(\x08\"\x1c\n\x0bUInt32Value\x12\r\n\x05value\x18\x01 \x01(\r\"j\n\nRangeField\x12\x1d\n\x02op\x18\x01 \x01(\x0e\x32\x11.pb.RangeField.Op\x12\r\n\x05value\x18\x02 \x03(\x05\".\n\x02Op\x12\x06\n\x02\x45Q\x10\x00\x12\x07\n\x03LTE\x10\x01\x12\x07\n\x03GTE\x10\x02\x12\x06\n\x02LT\x10\x03\x12\x06\n\x02GT\x10\x04\"\x8e\x0c\n\rSearchRequest\x12%\n\x08\x63laim_id\x18\x01 \x01(\x0b\x32\x13.pb.InvertibleField\x12\'\n\nchannel_id\x18\x02 \x01(\x0b\x32\x13.pb.InvertibleField\x12\x0c\n\x04text\x18\x03 \x01(\t\x12\r\n\x05limit\x18\x04 \x01(\x05\x12\x10\n\x08order_by\x18\x05 \x03(\t\x12\x0e\n\x06offset\x18\x06 \x01(\r\x12\x16\n\x0eis_controlling\x18\x07 \x01(\x08\x12\x1d\n\x15last_take_over_height\x18\x08 \x01(\t\x12\x12\n\nclaim_name\x18\t \x01(\t\x12\x17\n\x0fnormalized_name\x18\n \x01(\t\x12#\n\x0btx_position\x18\x0b \x03(\x0b\x32\x0e.pb.RangeField\x12\x1e\n\x06\x61mount\x18\x0c \x03(\x0b\x32\x0e.pb.RangeField\x12!\n\ttimestamp\x18\r \x03(\x0b\x32\x0e.pb.RangeField\x12*\n\x12\x63reation_timestamp\x18\x0e \x03(\x0b\x32\x0e.pb.RangeField\x12\x1e\n\x06height\x18\x0f \x03(\x0b\x32\x0e.pb.RangeField\x12\'\n\x0f\x63reation_height\x18\x10 \x03(\x0b\x32\x0e.pb.RangeField\x12)\n\x11\x61\x63tivation_height\x18\x11 \x03(\x0b\x32\x0e.pb.RangeField\x12)\n\x11\x65xpiration_height\x18\x12 \x03(\x0b\x32\x0e.pb.RangeField\x12$\n\x0crelease_time\x18\x13 \x03(\x0b\x32\x0e.pb.RangeField\x12\x11\n\tshort_url\x18\x14 \x01(\t\x12\x15\n\rcanonical_url\x18\x15 \x01(\t\x12\r\n\x05title\x18\x16 \x01(\t\x12\x0e\n\x06\x61uthor\x18\x17 \x01(\t\x12\x13\n\x0b\x64\x65scription\x18\x18 \x01(\t\x12\x12\n\nclaim_type\x18\x19 \x03(\t\x12$\n\x0crepost_count\x18\x1a \x03(\x0b\x32\x0e.pb.RangeField\x12\x13\n\x0bstream_type\x18\x1b \x03(\t\x12\x12\n\nmedia_type\x18\x1c \x03(\t\x12\"\n\nfee_amount\x18\x1d \x03(\x0b\x32\x0e.pb.RangeField\x12\x14\n\x0c\x66\x65\x65_currency\x18\x1e \x01(\t\x12 \n\x08\x64uration\x18\x1f \x03(\x0b\x32\x0e.pb.RangeField\x12\x19\n\x11reposted_claim_id\x18  \x01(\t\x12#\n\x0b\x63\x65nsor_type\x18! \x03(\x0b\x32\x0e.pb.RangeField\x12\x19\n\x11\x63laims_in_channel\x18\" \x01(\t\x12)\n\x12is_signature_valid\x18$ \x01(\x0b\x32\r.pb.BoolValue\x12(\n\x10\x65\x66\x66\x65\x63tive_amount\x18% \x03(\x0b\x32\x0e.pb.RangeField\x12&\n\x0esupport_amount\x18& \x03(\x0b\x32\x0e.pb.RangeField\x12&\n\x0etrending_score\x18\' \x03(\x0b\x32\x0e.pb.RangeField\x12\r\n\x05tx_id\x18+ \x01(\t\x12 \n\x07tx_nout\x18, \x01(\x0b\x32\x0f.pb.UInt32Value\x12\x11\n\tsignature\x18- \x01(\t\x12\x18\n\x10signature_digest\x18. \x01(\t\x12\x18\n\x10public_key_bytes\x18/ \x01(\t\x12\x15\n\rpublic_key_id\x18\x30 \x01(\t\x12\x10\n\x08\x61ny_tags\x18\x31 \x03(\t\x12\x10\n\x08\x61ll_tags\x18\x32 \x03(\t\x12\x10\n\x08not_tags\x18\x33 \x03(\t\x12\x1d\n\x15has_channel_signature\x18\x34 \x01(\x08\x12!\n\nhas_source\x18\x35 \x01(\x0b\x32\r.pb.BoolValue\x12 \n\x18limit_claims_per_channel\x18\x36 \x01(\x05\x12\x15\n\rany_languages\x18\x37 \x03(\t\x12\x15\n\rall_languages\x18\x38 \x03(\t\x12\x19\n\x11remove_duplicates\x18\x39 \x01(\x08\x12\x11\n\tno_totals\x18: \x01(\x08\x12\x0f\n\x07sd_hash\x18; \x01(\t2\x9b\x04\n\x03Hub\x12*\n\x06Search\x12\x11.pb.SearchRequest\x1a\x0b.pb.Outputs\"\x00\x12+\n\x04Ping\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12-\n\x05Hello\x12\x10.pb.HelloMessage\x1a\x10.pb.HelloMessage\"\x00\x12/\n\x07\x41\x64\x64Peer\x12\x11.pb.ServerMessage\x1a\x0f.pb.StringValue\"\x00\x12\x35\n\rPeerSubscribe\x12\x11.pb.ServerMessage\x1a\x0f.pb.StringValue\"\x00\x12.\n\x07Version\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12/\n\x08\x46\x65\x61tures\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12\x30\n\tBroadcast\x12\x10.pb.EmptyMessage\x1a\x0f.pb.UInt32Value\"\x00\x12-\n\x06Height\x12\x10.pb.EmptyMessage\x1a\x0f.pb.UInt32Value\"\x00\x12\x37\n\x0fHeightSubscribe\x12\x0f.pb.UInt32Value\x1a\x0f.pb.UInt32Value\"\x00\x30\x01\x12)\n\x07Resolve\x12\x0f.pb.StringArray\x1a\x0b.pb.Outputs\"\x00\x42,Z*github.com/lbryio/herald.go/protobuf/go/pbb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\thub.proto\x12\x02pb\x1a\x0cresult.proto\"\x0e\n\x0c\x45mptyMessage\".\n\rServerMessage\x12\x0f\n\x07\x61\x64\x64ress\x18\x01 \x01(\t\x12\x0c\n\x04port\x18\x02 \x01(\t\"N\n\x0cHelloMessage\x12\x0c\n\x04port\x18\x01 \x01(\t\x12\x0c\n\x04host\x18\x02 \x01(\t\x12\"\n\x07servers\x18\x03 \x03(\x0b\x32\x11.pb.ServerMessage\"0\n\x0fInvertibleField\x12\x0e\n\x06invert\x18\x01 \x01(\x08\x12\r\n\x05value\x18\x02 \x03(\t\"\x1c\n\x0bStringValue\x12\r\n\x05value\x18\x01 \x01(\t\"\x1c\n\x0bStringArray\x12\r\n\x05value\x18\x01 \x03(\t\"\x1a\n\tBoolValue\x12\r\n\x05value\x18\x01 \x01(\x08\"\x1c\n\x0bUInt32Value\x12\r\n\x05value\x18\x01 \x01(\r\"j\n\nRangeField\x12\x1d\n\x02op\x18\x01 \x01(\x0e\x32\x11.pb.RangeField.Op\x12\r\n\x05value\x18\x02 \x03(\x05\".\n\x02Op\x12\x06\n\x02\x45Q\x10\x00\x12\x07\n\x03LTE\x10\x01\x12\x07\n\x03GTE\x10\x02\x12\x06\n\x02LT\x10\x03\x12\x06\n\x02GT\x10\x04\"\x8e\x0c\n\rSearchRequest\x12%\n\x08\x63laim_id\x18\x01 \x01(\x0b\x32\x13.pb.InvertibleField\x12\'\n\nchannel_id\x18\x02 \x01(\x0b\x32\x13.pb.InvertibleField\x12\x0c\n\x04text\x18\x03 \x01(\t\x12\r\n\x05limit\x18\x04 \x01(\x05\x12\x10\n\x08order_by\x18\x05 \x03(\t\x12\x0e\n\x06offset\x18\x06 \x01(\r\x12\x16\n\x0eis_controlling\x18\x07 \x01(\x08\x12\x1d\n\x15last_take_over_height\x18\x08 \x01(\t\x12\x12\n\nclaim_name\x18\t \x01(\t\x12\x17\n\x0fnormalized_name\x18\n \x01(\t\x12#\n\x0btx_position\x18\x0b \x03(\x0b\x32\x0e.pb.RangeField\x12\x1e\n\x06\x61mount\x18\x0c \x03(\x0b\x32\x0e.pb.RangeField\x12!\n\ttimestamp\x18\r \x03(\x0b\x32\x0e.pb.RangeField\x12*\n\x12\x63reation_timestamp\x18\x0e \x03(\x0b\x32\x0e.pb.RangeField\x12\x1e\n\x06height\x18\x0f \x03(\x0b\x32\x0e.pb.RangeField\x12\'\n\x0f\x63reation_height\x18\x10 \x03(\x0b\x32\x0e.pb.RangeField\x12)\n\x11\x61\x63tivation_height\x18\x11 \x03(\x0b\x32\x0e.pb.RangeField\x12)\n\x11\x65xpiration_height\x18\x12 \x03(\x0b\x32\x0e.pb.RangeField\x12$\n\x0crelease_time\x18\x13 \x03(\x0b\x32\x0e.pb.RangeField\x12\x11\n\tshort_url\x18\x14 \x01(\t\x12\x15\n\rcanonical_url\x18\x15 \x01(\t\x12\r\n\x05title\x18\x16 \x01(\t\x12\x0e\n\x06\x61uthor\x18\x17 \x01(\t\x12\x13\n\x0b\x64\x65scription\x18\x18 \x01(\t\x12\x12\n\nclaim_type\x18\x19 \x03(\t\x12$\n\x0crepost_count\x18\x1a \x03(\x0b\x32\x0e.pb.RangeField\x12\x13\n\x0bstream_type\x18\x1b \x03(\t\x12\x12\n\nmedia_type\x18\x1c \x03(\t\x12\"\n\nfee_amount\x18\x1d \x03(\x0b\x32\x0e.pb.RangeField\x12\x14\n\x0c\x66\x65\x65_currency\x18\x1e \x01(\t\x12 \n\x08\x64uration\x18\x1f \x03(\x0b\x32\x0e.pb.RangeField\x12\x19\n\x11reposted_claim_id\x18  \x01(\t\x12#\n\x0b\x63\x65nsor_type\x18! \x03(\x0b\x32\x0e.pb.RangeField\x12\x19\n\x11\x63laims_in_channel\x18\" \x01(\t\x12)\n\x12is_signature_valid\x18$ \x01(\x0b\x32\r.pb.BoolValue\x12(\n\x10\x65\x66\x66\x65\x63tive_amount\x18% \x03(\x0b\x32\x0e.pb.RangeField\x12&\n\x0esupport_amount\x18& \x03(\x0b\x32\x0e.pb.RangeField\x12&\n\x0etrending_score\x18\' \x03(\x0b\x32\x0e.pb.RangeField\x12\r\n\x05tx_id\x18+ \x01(\t\x12 \n\x07tx_nout\x18, \x01(\x0b\x32\x0f.pb.UInt32Value\x12\x11\n\tsignature\x18- \x01(\t\x12\x18\n\x10signature_digest\x18. \x01(\t\x12\x18\n\x10public_key_bytes\x18/ \x01(\t\x12\x15\n\rpublic_key_id\x18\x30 \x01(\t\x12\x10\n\x08\x61ny_tags\x18\x31 \x03(\t\x12\x10\n\x08\x61ll_tags\x18\x32 \x03(\t\x12\x10\n\x08not_tags\x18\x33 \x03(\t\x12\x1d\n\x15has_channel_signature\x18\x34 \x01(\x08\x12!\n\nhas_source\x18\x35 \x01(\x0b\x32\r.pb.BoolValue\x12 \n\x18limit_claims_per_channel\x18\x36 \x01(\x05\x12\x15\n\rany_languages\x18\x37 \x03(\t\x12\x15\n\rall_languages\x18\x38 \x03(\t\x12\x19\n\x11remove_duplicates\x18\x39 \x01(\x08\x12\x11\n\tno_totals\x18: \x01(\x08\x12\x0f\n\x07sd_hash\x18; \x01(\t\"|\n\x0eHistoryRequest\x12\x12\n\nscripthash\x18\x01 \x01(\t\x12\x0f\n\x07\x61\x64\x64ress\x18\x02 \x01(\t\x12\x13\n\x0b\x66rom_height\x18\x03 \x01(\r\x12\x11\n\tto_height\x18\x04 \x01(\r\x12\x0e\n\x06\x63ursor\x18\x05 \x01(\r\x12\r\n\x05limit\x18\x06 \x01(\r\".\n\x0bHistoryItem\x12\x0f\n\x07tx_hash\x18\x01 \x01(\t\x12\x0e\n\x06height\x18\x02 \x01(\r\"C\n\x0fHistoryResponse\x12 \n\x07history\x18\x01 \x03(\x0b\x32\x0f.pb.HistoryItem\x12\x0e\n\x06\x63ursor\x18\x02 \x01(\r\"C\n\x11ListClaimsRequest\x12\x0f\n\x07\x61\x64\x64ress\x18\x01 \x01(\t\x12\x0e\n\x06offset\x18\x02 \x01(\r\x12\r\n\x05limit\x18\x03 \x01(\r\"l\n\x14\x43hannelClaimsRequest\x12\x12\n\nchannel_id\x18\x01 \x01(\t\x12\x10\n\x08order_by\x18\x02 \x01(\t\x12\x0f\n\x07reverse\x18\x03 \x01(\x08\x12\x0e\n\x06offset\x18\x04 \x01(\r\x12\r\n\x05limit\x18\x05 \x01(\r\"_\n\x10\x43laimDiffRequest\x12\x13\n\x0b\x66rom_height\x18\x01 \x01(\r\x12\x11\n\tto_height\x18\x02 \x01(\r\x12\x0f\n\x07resolve\x18\x03 \x01(\x08\x12\x12\n\nblock_hash\x18\x04 \x01(\x0c\"\x8c\x01\n\tClaimDiff\x12\x0e\n\x06height\x18\x01 \x01(\r\x12\x12\n\nblock_hash\x18\x02 \x01(\x0c\x12\x16\n\x0etouched_claims\x18\x03 \x03(\x0c\x12\x16\n\x0e\x64\x65leted_claims\x18\x04 \x03(\x0c\x12\x1c\n\x07outputs\x18\x05 \x01(\x0b\x32\x0b.pb.Outputs\x12\r\n\x05reorg\x18\x06 \x01(\x08\"\x8c\x01\n\rStatsResponse\x12\x0e\n\x06height\x18\x01 \x01(\r\x12\x0b\n\x03tip\x18\x02 \x01(\t\x12\x10\n\x08tx_count\x18\x03 \x01(\r\x12\x0e\n\x06\x63laims\x18\x04 \x01(\x03\x12\x10\n\x08supports\x18\x05 \x01(\x03\x12\x14\n\x0c\x61\x63tive_names\x18\x06 \x01(\x03\x12\x14\n\x0ctotal_staked\x18\x07 \x01(\x03\x32\xd2\x06\n\x03Hub\x12*\n\x06Search\x12\x11.pb.SearchRequest\x1a\x0b.pb.Outputs\"\x00\x12+\n\x04Ping\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12-\n\x05Hello\x12\x10.pb.HelloMessage\x1a\x10.pb.HelloMessage\"\x00\x12/\n\x07\x41\x64\x64Peer\x12\x11.pb.ServerMessage\x1a\x0f.pb.StringValue\"\x00\x12\x35\n\rPeerSubscribe\x12\x11.pb.ServerMessage\x1a\x0f.pb.StringValue\"\x00\x12.\n\x07Version\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12/\n\x08\x46\x65\x61tures\x12\x10.pb.EmptyMessage\x1a\x0f.pb.StringValue\"\x00\x12\x30\n\tBroadcast\x12\x10.pb.EmptyMessage\x1a\x0f.pb.UInt32Value\"\x00\x12-\n\x06Height\x12\x10.pb.EmptyMessage\x1a\x0f.pb.UInt32Value\"\x00\x12\x37\n\x0fHeightSubscribe\x12\x0f.pb.UInt32Value\x1a\x0f.pb.UInt32Value\"\x00\x30\x01\x12)\n\x07Resolve\x12\x0f.pb.StringArray\x1a\x0b.pb.Outputs\"\x00\x12\x34\n\x07History\x12\x12.pb.HistoryRequest\x1a\x13.pb.HistoryResponse\"\x00\x12\x32\n\nListClaims\x12\x15.pb.ListClaimsRequest\x1a\x0b.pb.Outputs\"\x00\x12\x38\n\rChannelClaims\x12\x18.pb.ChannelClaimsRequest\x1a\x0b.pb.Outputs\"\x00\x12*\n\x08Trending\x12\x0f.pb.UInt32Value\x1a\x0b.pb.Outputs\"\x00\x12\x35\n\nClaimDiffs\x12\x14.pb.ClaimDiffRequest\x1a\r.pb.ClaimDiff\"\x00\x30\x01\x12.\n\x05Stats\x12\x10.pb.EmptyMessage\x1a\x11.pb.StatsResponse\"\x00\x42,Z*github.com/lbryio/herald.go/protobuf/go/pbb\x06proto3')
 
 
 
@@ -29,6 +29,14 @@ _BOOLVALUE = DESCRIPTOR.message_types_by_name['BoolValue']
 _UINT32VALUE = DESCRIPTOR.message_types_by_name['UInt32Value']
 _RANGEFIELD = DESCRIPTOR.message_types_by_name['RangeField']
 _SEARCHREQUEST = DESCRIPTOR.message_types_by_name['SearchRequest']
+_HISTORYREQUEST = DESCRIPTOR.message_types_by_name['HistoryRequest']
+_HISTORYITEM = DESCRIPTOR.message_types_by_name['HistoryItem']
+_HISTORYRESPONSE = DESCRIPTOR.message_types_by_name['HistoryResponse']
+_LISTCLAIMSREQUEST = DESCRIPTOR.message_types_by_name['ListClaimsRequest']
+_CHANNELCLAIMSREQUEST = DESCRIPTOR.message_types_by_name['ChannelClaimsRequest']
+_CLAIMDIFFREQUEST = DESCRIPTOR.message_types_by_name['ClaimDiffRequest']
+_CLAIMDIFF = DESCRIPTOR.message_types_by_name['ClaimDiff']
+_STATSRESPONSE = DESCRIPTOR.message_types_by_name['StatsResponse']
 _RANGEFIELD_OP = _RANGEFIELD.enum_types_by_name['Op']
 EmptyMessage = _reflection.GeneratedProtocolMessageType('EmptyMessage', (_message.Message,), {
   'DESCRIPTOR' : _EMPTYMESSAGE,
@@ -100,6 +108,62 @@ SearchRequest = _reflection.GeneratedProtocolMessageType('SearchRequest', (_mess
   })
 _sym_db.RegisterMessage(SearchRequest)
 
+HistoryRequest = _reflection.GeneratedProtocolMessageType('HistoryRequest', (_message.Message,), {
+  'DESCRIPTOR' : _HISTORYREQUEST,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.HistoryRequest)
+  })
+_sym_db.RegisterMessage(HistoryRequest)
+
+HistoryItem = _reflection.GeneratedProtocolMessageType('HistoryItem', (_message.Message,), {
+  'DESCRIPTOR' : _HISTORYITEM,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.HistoryItem)
+  })
+_sym_db.RegisterMessage(HistoryItem)
+
+HistoryResponse = _reflection.GeneratedProtocolMessageType('HistoryResponse', (_message.Message,), {
+  'DESCRIPTOR' : _HISTORYRESPONSE,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.HistoryResponse)
+  })
+_sym_db.RegisterMessage(HistoryResponse)
+
+ListClaimsRequest = _reflection.GeneratedProtocolMessageType('ListClaimsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _LISTCLAIMSREQUEST,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.ListClaimsRequest)
+  })
+_sym_db.RegisterMessage(ListClaimsRequest)
+
+ChannelClaimsRequest = _reflection.GeneratedProtocolMessageType('ChannelClaimsRequest', (_message.Message,), {
+  'DESCRIPTOR' : _CHANNELCLAIMSREQUEST,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.ChannelClaimsRequest)
+  })
+_sym_db.RegisterMessage(ChannelClaimsRequest)
+
+ClaimDiffRequest = _reflection.GeneratedProtocolMessageType('ClaimDiffRequest', (_message.Message,), {
+  'DESCRIPTOR' : _CLAIMDIFFREQUEST,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.ClaimDiffRequest)
+  })
+_sym_db.RegisterMessage(ClaimDiffRequest)
+
+ClaimDiff = _reflection.GeneratedProtocolMessageType('ClaimDiff', (_message.Message,), {
+  'DESCRIPTOR' : _CLAIMDIFF,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.ClaimDiff)
+  })
+_sym_db.RegisterMessage(ClaimDiff)
+
+StatsResponse = _reflection.GeneratedProtocolMessageType('StatsResponse', (_message.Message,), {
+  'DESCRIPTOR' : _STATSRESPONSE,
+  '__module__' : 'hub_pb2'
+  # @@protoc_insertion_point(class_scope:pb.StatsResponse)
+  })
+_sym_db.RegisterMessage(StatsResponse)
+
 _HUB = DESCRIPTOR.services_by_name['Hub']
 if _descriptor._USE_C_DESCRIPTORS == False:
 
@@ -127,6 +191,22 @@ if _descriptor._USE_C_DESCRIPTORS == False:
   _RANGEFIELD_OP._serialized_end=449
   _SEARCHREQUEST._serialized_start=452
   _SEARCHREQUEST._serialized_end=2002
-  _HUB._serialized_start=2005
-  _HUB._serialized_end=2544
+  _HISTORYREQUEST._serialized_start=2004
+  _HISTORYREQUEST._serialized_end=2128
+  _HISTORYITEM._serialized_start=2130
+  _HISTORYITEM._serialized_end=2176
+  _HISTORYRESPONSE._serialized_start=2178
+  _HISTORYRESPONSE._serialized_end=2245
+  _LISTCLAIMSREQUEST._serialized_start=2247
+  _LISTCLAIMSREQUEST._serialized_end=2314
+  _CHANNELCLAIMSREQUEST._serialized_start=2316
+  _CHANNELCLAIMSREQUEST._serialized_end=2424
+  _CLAIMDIFFREQUEST._serialized_start=2426
+  _CLAIMDIFFREQUEST._serialized_end=2521
+  _CLAIMDIFF._serialized_start=2524
+  _CLAIMDIFF._serialized_end=2664
+  _STATSRESPONSE._serialized_start=2667
+  _STATSRESPONSE._serialized_end=2807
+  _HUB._serialized_start=2810
+  _HUB._serialized_end=3660
 # @@protoc_insertion_point(module_scope)
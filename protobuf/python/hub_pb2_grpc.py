@@ -70,6 +70,36 @@ class HubStub(object):
                 request_serializer=hub__pb2.StringArray.SerializeToString,
                 response_deserializer=result__pb2.Outputs.FromString,
                 )
+        self.History = channel.unary_unary(
+                '/pb.Hub/History',
+                request_serializer=hub__pb2.HistoryRequest.SerializeToString,
+                response_deserializer=hub__pb2.HistoryResponse.FromString,
+                )
+        self.ListClaims = channel.unary_unary(
+                '/pb.Hub/ListClaims',
+                request_serializer=hub__pb2.ListClaimsRequest.SerializeToString,
+                response_deserializer=result__pb2.Outputs.FromString,
+                )
+        self.ChannelClaims = channel.unary_unary(
+                '/pb.Hub/ChannelClaims',
+                request_serializer=hub__pb2.ChannelClaimsRequest.SerializeToString,
+                response_deserializer=result__pb2.Outputs.FromString,
+                )
+        self.Trending = channel.unary_unary(
+                '/pb.Hub/Trending',
+                request_serializer=hub__pb2.UInt32Value.SerializeToString,
+                response_deserializer=result__pb2.Outputs.FromString,
+                )
+        self.ClaimDiffs = channel.unary_stream(
+                '/pb.Hub/ClaimDiffs',
+                request_serializer=hub__pb2.ClaimDiffRequest.SerializeToString,
+                response_deserializer=hub__pb2.ClaimDiff.FromString,
+                )
+        self.Stats = channel.unary_unary(
+                '/pb.Hub/Stats',
+                request_serializer=hub__pb2.EmptyMessage.SerializeToString,
+                response_deserializer=hub__pb2.StatsResponse.FromString,
+                )
 
 
 class HubServicer(object):
@@ -141,6 +171,42 @@ class HubServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def History(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ListClaims(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ChannelClaims(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Trending(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def ClaimDiffs(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
+    def Stats(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_HubServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -199,6 +265,36 @@ def add_HubServicer_to_server(servicer, server):
                     request_deserializer=hub__pb2.StringArray.FromString,
                     response_serializer=result__pb2.Outputs.SerializeToString,
             ),
+            'History': grpc.unary_unary_rpc_method_handler(
+                    servicer.History,
+                    request_deserializer=hub__pb2.HistoryRequest.FromString,
+                    response_serializer=hub__pb2.HistoryResponse.SerializeToString,
+            ),
+            'ListClaims': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListClaims,
+                    request_deserializer=hub__pb2.ListClaimsRequest.FromString,
+                    response_serializer=result__pb2.Outputs.SerializeToString,
+            ),
+            'ChannelClaims': grpc.unary_unary_rpc_method_handler(
+                    servicer.ChannelClaims,
+                    request_deserializer=hub__pb2.ChannelClaimsRequest.FromString,
+                    response_serializer=result__pb2.Outputs.SerializeToString,
+            ),
+            'Trending': grpc.unary_unary_rpc_method_handler(
+                    servicer.Trending,
+                    request_deserializer=hub__pb2.UInt32Value.FromString,
+                    response_serializer=result__pb2.Outputs.SerializeToString,
+            ),
+            'ClaimDiffs': grpc.unary_stream_rpc_method_handler(
+                    servicer.ClaimDiffs,
+                    request_deserializer=hub__pb2.ClaimDiffRequest.FromString,
+                    response_serializer=hub__pb2.ClaimDiff.SerializeToString,
+            ),
+            'Stats': grpc.unary_unary_rpc_method_handler(
+                    servicer.Stats,
+                    request_deserializer=hub__pb2.EmptyMessage.FromString,
+                    response_serializer=hub__pb2.StatsResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'pb.Hub', rpc_method_handlers)
@@ -395,3 +491,105 @@ class Hub(object):
             result__pb2.Outputs.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def History(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.Hub/History',
+            hub__pb2.HistoryRequest.SerializeToString,
+            hub__pb2.HistoryResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListClaims(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.Hub/ListClaims',
+            hub__pb2.ListClaimsRequest.SerializeToString,
+            result__pb2.Outputs.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ChannelClaims(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.Hub/ChannelClaims',
+            hub__pb2.ChannelClaimsRequest.SerializeToString,
+            result__pb2.Outputs.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Trending(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.Hub/Trending',
+            hub__pb2.UInt32Value.SerializeToString,
+            result__pb2.Outputs.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ClaimDiffs(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_stream(request, target, '/pb.Hub/ClaimDiffs',
+            hub__pb2.ClaimDiffRequest.SerializeToString,
+            hub__pb2.ClaimDiff.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def Stats(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pb.Hub/Stats',
+            hub__pb2.EmptyMessage.SerializeToString,
+            hub__pb2.StatsResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -31,6 +31,10 @@ type Args struct {
 	NotifierPort        string
 	JSONRPCPort         int
 	JSONRPCHTTPPort     int
+	AdminRPCPort        int
+	PublicHostnames     []string
+	PublicSSLPort       int
+	PublicWSPort        int
 	MaxSessions         int
 	SessionTimeout      int
 	EsIndex             string
@@ -76,6 +80,9 @@ const (
 	DefaultNotifierPort    = "18080"
 	DefaultJSONRPCPort     = 50001
 	DefaultJSONRPCHTTPPort = 50002
+	DefaultAdminRPCPort    = 0
+	DefaultPublicSSLPort   = 0
+	DefaultPublicWSPort    = 0
 	DefaultMaxSessions     = 10000
 	DefaultSessionTimeout  = 300
 	DefaultRefreshDelta    = 5
@@ -107,6 +114,7 @@ const (
 var (
 	DefaultBlockingChannelIds  = []string{}
 	DefaultFilteringChannelIds = []string{}
+	DefaultPublicHostnames     = []string{}
 )
 
 func loadBanner(bannerFile *string, serverVersion string) *string {
@@ -142,6 +150,10 @@ func MakeDefaultTestArgs() *Args {
 		NotifierPort:    DefaultNotifierPort,
 		JSONRPCPort:     DefaultJSONRPCPort,
 		JSONRPCHTTPPort: DefaultJSONRPCHTTPPort,
+		AdminRPCPort:    DefaultAdminRPCPort,
+		PublicHostnames: DefaultPublicHostnames,
+		PublicSSLPort:   DefaultPublicSSLPort,
+		PublicWSPort:    DefaultPublicWSPort,
 		MaxSessions:     DefaultMaxSessions,
 		SessionTimeout:  DefaultSessionTimeout,
 		EsIndex:         DefaultEsIndex,
@@ -224,6 +236,7 @@ func ParseArgs(searchRequest *pb.SearchRequest) *Args {
 	notifierPort := parser.String("", "notifier-port", &argparse.Options{Required: false, Help: "notifier port", Default: DefaultNotifierPort})
 	jsonRPCPort := parser.Int("", "json-rpc-port", &argparse.Options{Required: false, Help: "JSON RPC port", Validate: validatePort, Default: DefaultJSONRPCPort})
 	jsonRPCHTTPPort := parser.Int("", "json-rpc-http-port", &argparse.Options{Required: false, Help: "JSON RPC over HTTP port", Validate: validatePort, Default: DefaultJSONRPCHTTPPort})
+	adminRPCPort := parser.Int("", "admin-rpc-port", &argparse.Options{Required: false, Help: "Admin JSON RPC over HTTP port, listening on localhost only (0 to disable)", Validate: validatePort, Default: DefaultAdminRPCPort})
 	maxSessions := parser.Int("", "max-sessions", &argparse.Options{Required: false, Help: "Maximum number of electrum clients that can be connected", Default: DefaultMaxSessions})
 	sessionTimeout := parser.Int("", "session-timeout", &argparse.Options{Required: false, Help: "Session inactivity timeout (seconds)", Default: DefaultSessionTimeout})
 	esIndex := parser.String("", "esindex", &argparse.Options{Required: false, Help: "elasticsearch index name", Default: DefaultEsIndex})
@@ -236,6 +249,9 @@ func ParseArgs(searchRequest *pb.SearchRequest) *Args {
 	filteringChannelIds := parser.StringList("", "filtering-channel-ids", &argparse.Options{Required: false, Help: "Filtering channel ids", Default: DefaultFilteringChannelIds})
 
 	// arguments for server features
+	publicHostnames := parser.StringList("", "public-hostnames", &argparse.Options{Required: false, Help: "Public hostnames advertised by server.features", Default: DefaultPublicHostnames})
+	publicSSLPort := parser.Int("", "public-ssl-port", &argparse.Options{Required: false, Help: "Public SSL port advertised by server.features (0 for none)", Validate: validatePort, Default: DefaultPublicSSLPort})
+	publicWSPort := parser.Int("", "public-ws-port", &argparse.Options{Required: false, Help: "Public websocket port advertised by server.features (0 for none)", Validate: validatePort, Default: DefaultPublicWSPort})
 	serverDescription := parser.String("", "server-description", &argparse.Options{Required: false, Help: "Server description", Default: DefaultServerDescription})
 	paymentAddress := parser.String("", "payment-address", &argparse.Options{Required: false, Help: "Payment address", Default: DefaultPaymentAddress})
 	donationAddress := parser.String("", "donation-address", &argparse.Options{Required: false, Help: "Donation address", Default: DefaultDonationAddress})
@@ -291,6 +307,10 @@ func ParseArgs(searchRequest *pb.SearchRequest) *Args {
 		NotifierPort:        *notifierPort,
 		JSONRPCPort:         *jsonRPCPort,
 		JSONRPCHTTPPort:     *jsonRPCHTTPPort,
+		AdminRPCPort:        *adminRPCPort,
+		PublicHostnames:     *publicHostnames,
+		PublicSSLPort:       *publicSSLPort,
+		PublicWSPort:        *publicWSPort,
 		MaxSessions:         *maxSessions,
 		SessionTimeout:      *sessionTimeout,
 		EsIndex:             *esIndex,
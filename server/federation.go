@@ -3,11 +3,14 @@ package server
 import (
 	"bufio"
 	"context"
+	"encoding/json"
+	"fmt"
 	"log"
 	"math"
 	"net"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +26,30 @@ type Peer struct {
 	LastSeen time.Time
 }
 
+// ElectrumPeer is a server advertised to clients by 'server.peers.subscribe'.
+// Unlike a Peer it is never dialed, clients can add them with
+// 'server.add_peer'.
+type ElectrumPeer struct {
+	Host        string
+	IP          string
+	TcpPort     *int
+	SslPort     *int
+	ProtocolMax string
+	LastSeen    time.Time
+}
+
+const (
+	// maxElectrumPeers caps the electrum peer list, which clients can add to.
+	maxElectrumPeers = 100
+	// maxAddPeerHosts caps the hosts taken from the features of one peer.
+	maxAddPeerHosts = 10
+	// addPeerTimeout bounds resolving all the hosts of one peer.
+	addPeerTimeout = 2 * time.Second
+	// electrumPeerTimeout is how long an electrum peer is advertised
+	// after it was last added.
+	electrumPeerTimeout = 24 * time.Hour
+)
+
 var (
 	localHosts = map[string]bool{
 		"127.0.0.1": true,
@@ -369,6 +396,7 @@ func (s *Server) addPeer(newPeer *Peer, ping bool, subscribe bool) error {
 		metrics.PeersKnown.Inc()
 		s.writePeers()
 		s.notifyPeerSubs(newPeer)
+		go s.fetchPeerFeatures(newPeer)
 
 		// Subscribe to all our peers for now
 		if subscribe {
@@ -383,6 +411,130 @@ func (s *Server) addPeer(newPeer *Peer, ping bool, subscribe bool) error {
 	return nil
 }
 
+// publicIP resolves host to its first public IP.
+func publicIP(ctx context.Context, host string) (net.IP, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, addr := range addrs {
+		ip := addr.IP
+		if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+			ip.IsLinkLocalUnicast() || ip.IsMulticast() {
+			continue
+		}
+		return ip, nil
+	}
+	return nil, fmt.Errorf("no public ip for %v", host)
+}
+
+// expired returns whether the peer hasn't been added for
+// electrumPeerTimeout.
+func (peer *ElectrumPeer) expired(now time.Time) bool {
+	return now.Sub(peer.LastSeen) > electrumPeerTimeout
+}
+
+// addElectrumPeers adds the hosts in the features of a server to the
+// electrum peer list. Hosts must resolve to a public IP and serve TCP or
+// SSL, and are resolved concurrently under one deadline. Expired peers
+// make room for new ones. Returns whether any host was added or updated.
+func (s *Server) addElectrumPeers(features *ServerAddPeerReq) (bool, error) {
+	ourGenesis := genesisHash(s.Args, s.Chain)
+	if features.GenesisHash != ourGenesis {
+		return false, fmt.Errorf("genesis hash mismatch: %v", features.GenesisHash)
+	}
+	if len(features.Hosts) > maxAddPeerHosts {
+		return false, fmt.Errorf("too many hosts: %d > %d", len(features.Hosts), maxAddPeerHosts)
+	}
+	ours := make(map[string]bool, len(s.Args.PublicHostnames))
+	for _, hostname := range s.Args.PublicHostnames {
+		ours[hostname] = true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), addPeerTimeout)
+	defer cancel()
+	var wg sync.WaitGroup
+	peers := make(chan *ElectrumPeer, len(features.Hosts))
+	for host, ports := range features.Hosts {
+		if ours[host] || (ports.TcpPort == nil && ports.SslPort == nil) {
+			continue
+		}
+		wg.Add(1)
+		go func(host string, ports ServerFeaturesHost) {
+			defer wg.Done()
+			ip, err := publicIP(ctx, host)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			peers <- &ElectrumPeer{
+				Host:        host,
+				IP:          ip.String(),
+				TcpPort:     ports.TcpPort,
+				SslPort:     ports.SslPort,
+				ProtocolMax: features.ProtocolMax,
+			}
+		}(host, ports)
+	}
+	wg.Wait()
+	close(peers)
+
+	added := false
+	now := time.Now()
+	s.ElectrumPeersMut.Lock()
+	defer s.ElectrumPeersMut.Unlock()
+	if s.ElectrumPeers == nil {
+		s.ElectrumPeers = make(map[string]*ElectrumPeer)
+	}
+	for host, peer := range s.ElectrumPeers {
+		if peer.expired(now) {
+			delete(s.ElectrumPeers, host)
+		}
+	}
+	for peer := range peers {
+		if _, ok := s.ElectrumPeers[peer.Host]; ok || len(s.ElectrumPeers) < maxElectrumPeers {
+			peer.LastSeen = now
+			s.ElectrumPeers[peer.Host] = peer
+			added = true
+		}
+	}
+	return added, nil
+}
+
+// fetchPeerFeatures asks a federated peer for its features and adds the
+// hosts it serves clients on to the electrum peer list.
+func (s *Server) fetchPeerFeatures(peer *Peer) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx,
+		peer.Address+":"+peer.Port,
+		grpc.WithInsecure(),
+		grpc.WithBlock(),
+	)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer conn.Close()
+
+	c := pb.NewHubClient(conn)
+
+	res, err := c.Features(ctx, &pb.EmptyMessage{})
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	var features ServerAddPeerReq
+	if err := json.Unmarshal([]byte(res.Value), &features); err != nil {
+		log.Println(err)
+		return
+	}
+	if _, err := s.addElectrumPeers(&features); err != nil {
+		log.Println(err)
+	}
+}
+
 // mergePeers is an internal convenience function to add a list of
 // peers.
 func (s *Server) mergePeers(servers []*pb.ServerMessage) {
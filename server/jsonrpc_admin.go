@@ -0,0 +1,174 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	gorilla_mux "github.com/gorilla/mux"
+	gorilla_rpc "github.com/gorilla/rpc"
+	gorilla_json "github.com/gorilla/rpc/json"
+	log "github.com/sirupsen/logrus"
+)
+
+// AdminService provides the 'admin.*' endpoints for inspecting
+// and controlling electrum sessions. It is only served on the
+// admin port, which listens on localhost.
+type AdminService struct {
+	sessionMgr *sessionManager
+}
+
+type AdminSessionsReq struct{}
+
+type AdminSessionInfo struct {
+	Id            uint64  `json:"id"`
+	Addr          string  `json:"addr"`
+	ClientName    string  `json:"client_name"`
+	ClientVersion string  `json:"client_version"`
+	Protocol      string  `json:"protocol"`
+	HeadersSub    bool    `json:"headers_sub"`
+	HashXSubs     int     `json:"hashx_subs"`
+	LastRecv      int64   `json:"last_recv"`
+	LastSend      int64   `json:"last_send"`
+	Cost          float64 `json:"cost"`
+}
+
+type AdminSessionsResp struct {
+	MaxSessions int                 `json:"max_sessions"`
+	Sessions    []*AdminSessionInfo `json:"sessions"`
+}
+
+// Sessions is the json rpc endpoint for 'admin.sessions'.
+func (s *AdminService) Sessions(req *AdminSessionsReq, resp **AdminSessionsResp) error {
+	sm := s.sessionMgr
+	sm.sessionsMut.RLock()
+	defer sm.sessionsMut.RUnlock()
+	result := &AdminSessionsResp{
+		MaxSessions: sm.sessionsMax,
+		Sessions:    make([]*AdminSessionInfo, 0, len(sm.sessions)),
+	}
+	now := time.Now()
+	for _, sess := range sm.sessions {
+		elapsed := now.Sub(sess.costUpdated).Seconds()
+		info := &AdminSessionInfo{
+			Id:            uint64(sess.id),
+			Addr:          sess.addr.String(),
+			ClientName:    sess.clientName,
+			ClientVersion: sess.clientVersion,
+			Protocol:      sess.protocol.String(),
+			HeadersSub:    sess.headersSub,
+			HashXSubs:     len(sess.hashXSubs),
+			LastRecv:      sess.lastRecv.Unix(),
+			LastSend:      sess.lastSend.Unix(),
+			Cost:          max(0, sess.cost-elapsed*sessionCostDecayPerSec),
+		}
+		result.Sessions = append(result.Sessions, info)
+	}
+	*resp = result
+	return nil
+}
+
+type AdminDisconnectReq struct {
+	Id uint64 `json:"id"`
+}
+
+type AdminDisconnectResp struct {
+	Disconnected int `json:"disconnected"`
+}
+
+// Disconnect is the json rpc endpoint for 'admin.disconnect'.
+func (s *AdminService) Disconnect(req *AdminDisconnectReq, resp **AdminDisconnectResp) error {
+	count := s.sessionMgr.disconnect(func(sess *session) bool {
+		return uint64(sess.id) == req.Id
+	})
+	if count == 0 {
+		err := fmt.Errorf("no session with id %v", req.Id)
+		log.Warn(err)
+		return err
+	}
+	*resp = &AdminDisconnectResp{Disconnected: count}
+	return nil
+}
+
+type AdminDisconnectIPReq struct {
+	// Prefix is either a CIDR ("10.0.0.0/8") or a literal
+	// prefix of the textual IP address ("10.0.").
+	Prefix string `json:"prefix"`
+}
+
+// Disconnect_ip is the json rpc endpoint for 'admin.disconnect_ip'.
+func (s *AdminService) Disconnect_ip(req *AdminDisconnectIPReq, resp **AdminDisconnectResp) error {
+	if req.Prefix == "" {
+		err := fmt.Errorf("empty ip prefix")
+		log.Warn(err)
+		return err
+	}
+	var match func(ip string) bool
+	if strings.Contains(req.Prefix, "/") {
+		_, ipNet, err := net.ParseCIDR(req.Prefix)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		match = func(ip string) bool {
+			parsed := net.ParseIP(ip)
+			return parsed != nil && ipNet.Contains(parsed)
+		}
+	} else {
+		match = func(ip string) bool {
+			return strings.HasPrefix(ip, req.Prefix)
+		}
+	}
+	count := s.sessionMgr.disconnect(func(sess *session) bool {
+		host, _, err := net.SplitHostPort(sess.addr.String())
+		if err != nil {
+			host = sess.addr.String()
+		}
+		return match(host)
+	})
+	*resp = &AdminDisconnectResp{Disconnected: count}
+	return nil
+}
+
+type AdminSetMaxSessionsReq struct {
+	MaxSessions int `json:"max_sessions"`
+}
+
+type AdminSetMaxSessionsResp struct {
+	MaxSessions int `json:"max_sessions"`
+}
+
+// Set_max_sessions is the json rpc endpoint for 'admin.set_max_sessions'.
+func (s *AdminService) Set_max_sessions(req *AdminSetMaxSessionsReq, resp **AdminSetMaxSessionsResp) error {
+	if req.MaxSessions < 0 {
+		err := fmt.Errorf("invalid max_sessions: %v", req.MaxSessions)
+		log.Warn(err)
+		return err
+	}
+	s.sessionMgr.setMaxSessions(req.MaxSessions)
+	*resp = &AdminSetMaxSessionsResp{MaxSessions: req.MaxSessions}
+	return nil
+}
+
+// StartAdminRPC starts the admin json rpc over HTTP server. It listens
+// on localhost only and blocks until the listener fails.
+func (s *Server) StartAdminRPC() error {
+	s1 := gorilla_rpc.NewServer()
+	s1.RegisterCodec(&gorillaRpcCodec{gorilla_json.NewCodec()}, "application/json")
+
+	// Register "admin.*" handlers.
+	err := s1.RegisterTCPService(&AdminService{s.sessionManager}, "admin")
+	if err != nil {
+		log.Errorf("RegisterTCPService: %v\n", err)
+		return err
+	}
+
+	r := gorilla_mux.NewRouter()
+	r.Handle("/rpc", s1)
+	addr := "127.0.0.1:" + strconv.FormatUint(uint64(s.Args.AdminRPCPort), 10)
+	log.Infof("Admin JSONRPC server listening on %s", addr)
+	return http.ListenAndServe(addr, r)
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net"
+	"testing"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbry.go/v3/extras/stop"
+)
+
+func TestAdminSessions(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	grp := stop.NewDebug()
+	sm := newSessionManager(nil, args, grp, &chaincfg.RegressionNetParams)
+	sm.start()
+	defer sm.stop()
+
+	s := &AdminService{sm}
+
+	var maxResp *AdminSetMaxSessionsResp
+	err := s.Set_max_sessions(&AdminSetMaxSessionsReq{MaxSessions: 1}, &maxResp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+
+	_, server1 := net.Pipe()
+	sess1 := sm.addSession(server1)
+	if sess1 == nil {
+		t.Fatalf("first session rejected")
+	}
+	_, server2 := net.Pipe()
+	if sess2 := sm.addSession(server2); sess2 != nil {
+		t.Errorf("second session accepted past max_sessions")
+	}
+	err = sm.setClientVersion(sess1, "test-client", "0.107.0", protoVersion{0, 107, 0})
+	if err != nil {
+		t.Fatalf("setClientVersion err: %v", err)
+	}
+
+	var sessResp *AdminSessionsResp
+	err = s.Sessions(&AdminSessionsReq{}, &sessResp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if len(sessResp.Sessions) != 1 || sessResp.MaxSessions != 1 {
+		t.Fatalf("unexpected sessions: %+v", sessResp)
+	}
+	if sessResp.Sessions[0].ClientName != "test-client" {
+		t.Errorf("bad client name: %v", sessResp.Sessions[0].ClientName)
+	}
+
+	// Addresses of net.Pipe() connections are reported as "pipe".
+	var discResp *AdminDisconnectResp
+	err = s.Disconnect_ip(&AdminDisconnectIPReq{Prefix: "pi"}, &discResp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if discResp.Disconnected != 1 {
+		t.Errorf("expected 1 disconnected, got %v", discResp.Disconnected)
+	}
+	err = s.Disconnect(&AdminDisconnectReq{Id: uint64(sess1.id)}, &discResp)
+	if err == nil {
+		t.Errorf("expected error disconnecting removed session")
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 
 	"github.com/lbryio/herald.go/db"
 	"github.com/lbryio/herald.go/internal"
+	pb "github.com/lbryio/herald.go/protobuf/go"
 	"github.com/lbryio/lbcd/chaincfg"
 	"github.com/lbryio/lbcd/chaincfg/chainhash"
 	"github.com/lbryio/lbcd/txscript"
@@ -46,6 +49,15 @@ type BlockchainAddressService struct {
 	session    *session
 }
 
+// BlockchainOutpointService methods handle "blockchain.outpoint.*" RPCs
+type BlockchainOutpointService struct {
+	DB    *db.ReadOnlyDBColumnFamily
+	Chain *chaincfg.Params
+	// needed for subscribe/unsubscribe
+	sessionMgr *sessionManager
+	session    *session
+}
+
 // BlockchainScripthashService methods handle "blockchain.scripthash.*" RPCs
 type BlockchainScripthashService struct {
 	DB    *db.ReadOnlyDBColumnFamily
@@ -333,8 +345,69 @@ func (s *BlockchainScripthashService) Get_balance(req *scripthashGetBalanceReq,
 	return err
 }
 
+// HistoryPageReq holds the optional pagination arguments of the
+// get_history methods. Cursor is returned by the previous page.
+type HistoryPageReq struct {
+	FromHeight *uint32 `json:"from_height"`
+	ToHeight   *uint32 `json:"to_height"`
+	Cursor     *uint32 `json:"cursor"`
+	Limit      int     `json:"limit"`
+}
+
+const (
+	// maxUnpaginatedHistory is the most history entries returned
+	// when no pagination arguments are given.
+	maxUnpaginatedHistory  = 10000
+	defaultHistoryPageSize = 1000
+	maxHistoryPageSize     = 10000
+)
+
+func (req *HistoryPageReq) paginated() bool {
+	return req.FromHeight != nil || req.ToHeight != nil || req.Cursor != nil || req.Limit != 0
+}
+
+// getHistoryPage looks up the confirmed history of hashX within the
+// requested range, returning the cursor of the next page if any.
+func getHistoryPage(DB *db.ReadOnlyDBColumnFamily, hashX []byte, req *HistoryPageReq) ([]TxInfo, *uint32, error) {
+	var fromHeight, toHeight uint32 = 0, math.MaxUint32
+	limit := maxUnpaginatedHistory
+	if req.paginated() {
+		if req.FromHeight != nil {
+			fromHeight = *req.FromHeight
+		}
+		if req.Cursor != nil {
+			fromHeight = max(fromHeight, *req.Cursor)
+		}
+		if req.ToHeight != nil {
+			toHeight = *req.ToHeight
+		}
+		limit = req.Limit
+		if limit <= 0 {
+			limit = defaultHistoryPageSize
+		}
+		limit = min(limit, maxHistoryPageSize)
+	}
+	dbTXs, next, err := DB.GetHistoryPage(hashX, fromHeight, toHeight, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	if next != nil && !req.paginated() {
+		return nil, nil, fmt.Errorf("history too large: more than %v transactions, use from_height, to_height or cursor to paginate", maxUnpaginatedHistory)
+	}
+	confirmed := make([]TxInfo, 0, len(dbTXs))
+	for _, tx := range dbTXs {
+		confirmed = append(confirmed,
+			TxInfo{
+				TxHash: tx.TxHash.String(),
+				Height: tx.Height,
+			})
+	}
+	return confirmed, next, nil
+}
+
 type AddressGetHistoryReq struct {
 	Address string `json:"address"`
+	HistoryPageReq
 }
 type TxInfo struct {
 	TxHash string `json:"tx_hash"`
@@ -347,6 +420,7 @@ type TxInfoFee struct {
 type AddressGetHistoryResp struct {
 	Confirmed   []TxInfo    `json:"confirmed"`
 	Unconfirmed []TxInfoFee `json:"unconfirmed"`
+	Cursor      *uint32     `json:"cursor,omitempty"`
 }
 
 // 'blockchain.address.get_history'
@@ -362,22 +436,15 @@ func (s *BlockchainAddressService) Get_history(req *AddressGetHistoryReq, resp *
 		return err
 	}
 	hashX := hashXScript(script, s.Chain)
-	dbTXs, err := s.DB.GetHistory(hashX)
+	confirmed, cursor, err := getHistoryPage(s.DB, hashX, &req.HistoryPageReq)
 	if err != nil {
 		log.Warn(err)
 		return err
 	}
-	confirmed := make([]TxInfo, 0, len(dbTXs))
-	for _, tx := range dbTXs {
-		confirmed = append(confirmed,
-			TxInfo{
-				TxHash: tx.TxHash.String(),
-				Height: tx.Height,
-			})
-	}
 	result := &AddressGetHistoryResp{
 		Confirmed:   confirmed,
 		Unconfirmed: []TxInfoFee{}, // TODO
+		Cursor:      cursor,
 	}
 	*resp = result
 	return err
@@ -385,10 +452,12 @@ func (s *BlockchainAddressService) Get_history(req *AddressGetHistoryReq, resp *
 
 type ScripthashGetHistoryReq struct {
 	ScriptHash string `json:"scripthash"`
+	HistoryPageReq
 }
 type ScripthashGetHistoryResp struct {
 	Confirmed   []TxInfo    `json:"confirmed"`
 	Unconfirmed []TxInfoFee `json:"unconfirmed"`
+	Cursor      *uint32     `json:"cursor,omitempty"`
 }
 
 // 'blockchain.scripthash.get_history'
@@ -399,22 +468,15 @@ func (s *BlockchainScripthashService) Get_history(req *ScripthashGetHistoryReq,
 		return err
 	}
 	hashX := hashX(scripthash)
-	dbTXs, err := s.DB.GetHistory(hashX)
+	confirmed, cursor, err := getHistoryPage(s.DB, hashX, &req.HistoryPageReq)
 	if err != nil {
 		log.Warn(err)
 		return err
 	}
-	confirmed := make([]TxInfo, 0, len(dbTXs))
-	for _, tx := range dbTXs {
-		confirmed = append(confirmed,
-			TxInfo{
-				TxHash: tx.TxHash.String(),
-				Height: tx.Height,
-			})
-	}
 	result := &ScripthashGetHistoryResp{
 		Confirmed:   confirmed,
 		Unconfirmed: []TxInfoFee{}, // TODO
+		Cursor:      cursor,
 	}
 	*resp = result
 	return err
@@ -470,14 +532,49 @@ func (s *BlockchainScripthashService) Get_mempool(req *ScripthashGetMempoolReq,
 type AddressListUnspentReq struct {
 	Address string `json:"address"`
 }
+
+// TXOInfo is an unspent output. Outputs which are claims or supports
+// are annotated with the claim ID, name and type (claim, update or
+// support).
 type TXOInfo struct {
-	TxHash string `json:"tx_hash"`
-	TxPos  uint16 `json:"tx_pos"`
-	Height uint32 `json:"height"`
-	Value  uint64 `json:"value"`
+	TxHash  string `json:"tx_hash"`
+	TxPos   uint16 `json:"tx_pos"`
+	Height  uint32 `json:"height"`
+	Value   uint64 `json:"value"`
+	ClaimId string `json:"claim_id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
 }
 type AddressListUnspentResp []TXOInfo
 
+// listUnspent returns the annotated unspent outputs of hashX.
+func listUnspent(DB *db.ReadOnlyDBColumnFamily, hashX []byte) ([]TXOInfo, error) {
+	dbTXOs, err := DB.GetUnspent(hashX)
+	if err != nil {
+		return nil, err
+	}
+	unspent := make([]TXOInfo, 0, len(dbTXOs))
+	for _, txo := range dbTXOs {
+		info := TXOInfo{
+			TxHash: txo.TxHash.String(),
+			TxPos:  txo.TxPos,
+			Height: txo.Height,
+			Value:  txo.Value,
+		}
+		claimInfo, err := DB.GetTXOClaimInfo(txo.TxNum, txo.TxPos)
+		if err != nil {
+			return nil, err
+		}
+		if claimInfo != nil {
+			info.ClaimId = hex.EncodeToString(claimInfo.ClaimHash)
+			info.Name = claimInfo.Name
+			info.Type = claimInfo.Type
+		}
+		unspent = append(unspent, info)
+	}
+	return unspent, nil
+}
+
 // 'blockchain.address.listunspent'
 func (s *BlockchainAddressService) Listunspent(req *AddressListUnspentReq, resp **AddressListUnspentResp) error {
 	address, err := lbcutil.DecodeAddress(req.Address, s.Chain)
@@ -491,16 +588,10 @@ func (s *BlockchainAddressService) Listunspent(req *AddressListUnspentReq, resp
 		return err
 	}
 	hashX := hashXScript(script, s.Chain)
-	dbTXOs, err := s.DB.GetUnspent(hashX)
-	unspent := make([]TXOInfo, 0, len(dbTXOs))
-	for _, txo := range dbTXOs {
-		unspent = append(unspent,
-			TXOInfo{
-				TxHash: txo.TxHash.String(),
-				TxPos:  txo.TxPos,
-				Height: txo.Height,
-				Value:  txo.Value,
-			})
+	unspent, err := listUnspent(s.DB, hashX)
+	if err != nil {
+		log.Warn(err)
+		return err
 	}
 	result := AddressListUnspentResp(unspent)
 	*resp = &result
@@ -520,22 +611,175 @@ func (s *BlockchainScripthashService) Listunspent(req *ScripthashListUnspentReq,
 		return err
 	}
 	hashX := hashX(scripthash)
-	dbTXOs, err := s.DB.GetUnspent(hashX)
-	unspent := make([]TXOInfo, 0, len(dbTXOs))
-	for _, txo := range dbTXOs {
-		unspent = append(unspent,
-			TXOInfo{
-				TxHash: txo.TxHash.String(),
-				TxPos:  txo.TxPos,
-				Height: txo.Height,
-				Value:  txo.Value,
-			})
+	unspent, err := listUnspent(s.DB, hashX)
+	if err != nil {
+		log.Warn(err)
+		return err
 	}
 	result := ScripthashListUnspentResp(unspent)
 	*resp = &result
 	return err
 }
 
+// DetailedBalance splits the confirmed balance by the kind of output.
+type DetailedBalance struct {
+	Spendable uint64 `json:"spendable"`
+	Claims    uint64 `json:"claims"`
+	Supports  uint64 `json:"supports"`
+}
+
+type GetBalanceDetailedResp struct {
+	Confirmed   DetailedBalance `json:"confirmed"`
+	Unconfirmed uint64          `json:"unconfirmed"`
+}
+
+// getBalanceDetailed sums the annotated unspent outputs of hashX.
+func getBalanceDetailed(DB *db.ReadOnlyDBColumnFamily, hashX []byte) (*GetBalanceDetailedResp, error) {
+	unspent, err := listUnspent(DB, hashX)
+	if err != nil {
+		return nil, err
+	}
+	_, unconfirmed, err := DB.GetBalance(hashX)
+	if err != nil {
+		return nil, err
+	}
+	result := &GetBalanceDetailedResp{Unconfirmed: unconfirmed}
+	for _, txo := range unspent {
+		switch txo.Type {
+		case db.TXOTypeClaim, db.TXOTypeUpdate:
+			result.Confirmed.Claims += txo.Value
+		case db.TXOTypeSupport:
+			result.Confirmed.Supports += txo.Value
+		default:
+			result.Confirmed.Spendable += txo.Value
+		}
+	}
+	return result, nil
+}
+
+// 'blockchain.address.get_balance_detailed'
+func (s *BlockchainAddressService) Get_balance_detailed(req *AddressGetBalanceReq, resp **GetBalanceDetailedResp) error {
+	address, err := lbcutil.DecodeAddress(req.Address, s.Chain)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	result, err := getBalanceDetailed(s.DB, hashXScript(script, s.Chain))
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
+// 'blockchain.scripthash.get_balance_detailed'
+func (s *BlockchainScripthashService) Get_balance_detailed(req *scripthashGetBalanceReq, resp **GetBalanceDetailedResp) error {
+	scripthash, err := decodeScriptHash(req.ScriptHash)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	result, err := getBalanceDetailed(s.DB, hashX(scripthash))
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
+// listClaims returns a page of the unspent claims and supports of hashX.
+// Claims are resolved into txos. Supports are returned as bare txos, with
+// the claims they support resolved into extra_txos. Total counts every
+// claim and support of hashX, not just the page.
+func listClaims(DB *db.ReadOnlyDBColumnFamily, hashX []byte, offset, limit int) (*pb.Outputs, error) {
+	claims, err := DB.GetAddressClaims(hashX)
+	if err != nil {
+		return nil, err
+	}
+	total := len(claims)
+	claims = claims[min(offset, total):min(offset+limit, total)]
+
+	txos := make([]*pb.Output, 0, len(claims))
+	extraTxos := make([]*pb.Output, 0)
+	supported := make(map[string]bool)
+	for _, txo := range claims {
+		if txo.Type != db.TXOTypeSupport {
+			res, err := DB.FsGetClaimByHash(txo.ClaimHash)
+			if err != nil {
+				return nil, err
+			}
+			txos = append(txos, res.ToOutput())
+			continue
+		}
+		txHash, err := DB.GetTxHash(txo.TxNum)
+		if err != nil {
+			return nil, err
+		}
+		txos = append(txos, &pb.Output{
+			TxHash: txHash,
+			Nout:   uint32(txo.TxPos),
+			Height: txo.Height,
+		})
+		claimId := hex.EncodeToString(txo.ClaimHash)
+		if supported[claimId] {
+			continue
+		}
+		supported[claimId] = true
+		// The supported claim may have been abandoned.
+		supportedTxo, err := DB.GetClaimTxo(txo.ClaimHash)
+		if err != nil {
+			return nil, err
+		} else if supportedTxo == nil {
+			continue
+		}
+		res, err := DB.FsGetClaimByHash(txo.ClaimHash)
+		if err != nil {
+			return nil, err
+		}
+		extraTxos = append(extraTxos, res.ToOutput())
+	}
+	return &pb.Outputs{
+		Txos:      txos,
+		ExtraTxos: extraTxos,
+		Total:     uint32(total),
+		Offset:    uint32(offset),
+	}, nil
+}
+
+type AddressListClaimsReq struct {
+	Address string `json:"address"`
+	ClaimtriePageReq
+}
+
+// 'blockchain.address.list_claims'
+func (s *BlockchainAddressService) List_claims(req *AddressListClaimsReq, resp **pb.Outputs) error {
+	address, err := lbcutil.DecodeAddress(req.Address, s.Chain)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	offset, limit := req.page()
+	result, err := listClaims(s.DB, hashXScript(script, s.Chain), offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
 type AddressSubscribeReq []string
 type AddressSubscribeResp []string
 
@@ -626,3 +870,241 @@ func (s *BlockchainScripthashService) Unsubscribe(req *ScripthashSubscribeReq, r
 	*resp = (*ScripthashSubscribeResp)(nil)
 	return nil
 }
+
+// ScripthashManyReq is a list of scripthashes. A lone
+// scripthash is accepted too.
+type ScripthashManyReq []string
+
+func (req *ScripthashManyReq) UnmarshalJSON(b []byte) error {
+	var scripthash string
+	if err := json.Unmarshal(b, &scripthash); err == nil {
+		*req = ScripthashManyReq{scripthash}
+		return nil
+	}
+	var scripthashes []string
+	if err := json.Unmarshal(b, &scripthashes); err != nil {
+		return err
+	}
+	*req = scripthashes
+	return nil
+}
+
+// ScripthashStatusManyResp holds the status of each requested scripthash, in order.
+type ScripthashStatusManyResp []string
+
+// maxScripthashesPerRequest limits the size of the batch scripthash methods.
+const maxScripthashesPerRequest = 1000
+
+func decodeScriptHashes(scripthashes []string) ([][]byte, error) {
+	if len(scripthashes) > maxScripthashesPerRequest {
+		return nil, fmt.Errorf("too many scripthashes: %v (max %v)", len(scripthashes), maxScripthashesPerRequest)
+	}
+	hashXs := make([][]byte, 0, len(scripthashes))
+	for _, sh := range scripthashes {
+		scripthash, err := decodeScriptHash(sh)
+		if err != nil {
+			return nil, err
+		}
+		hashXs = append(hashXs, hashX(scripthash))
+	}
+	return hashXs, nil
+}
+
+func (s *BlockchainScripthashService) getStatuses(hashXs [][]byte) (*ScripthashStatusManyResp, error) {
+	statuses, err := s.DB.GetStatuses(hashXs)
+	if err != nil {
+		return nil, err
+	}
+	result := make(ScripthashStatusManyResp, len(statuses))
+	for i, status := range statuses {
+		result[i] = hex.EncodeToString(status)
+	}
+	return &result, nil
+}
+
+// 'blockchain.scripthash.subscribe_many'
+func (s *BlockchainScripthashService) Subscribe_many(req *ScripthashManyReq, resp **ScripthashStatusManyResp) error {
+	if s.sessionMgr == nil || s.session == nil {
+		return errors.New("no session, rpc not supported")
+	}
+	hashXs, err := decodeScriptHashes(*req)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	s.sessionMgr.hashXSubscribeMany(s.session, hashXs, *req, true /*subscribe*/)
+
+	result, err := s.getStatuses(hashXs)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
+// 'blockchain.scripthash.get_status_many'
+func (s *BlockchainScripthashService) Get_status_many(req *ScripthashManyReq, resp **ScripthashStatusManyResp) error {
+	hashXs, err := decodeScriptHashes(*req)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	result, err := s.getStatuses(hashXs)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
+// OutpointReq is the [tx_hash, txout] argument of 'blockchain.outpoint.*'.
+type OutpointReq struct {
+	TxHash string
+	Nout   uint32
+}
+
+func (req *OutpointReq) UnmarshalJSON(b []byte) error {
+	var args []json.RawMessage
+	if err := json.Unmarshal(b, &args); err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return fmt.Errorf("expected [tx_hash, txout], got %v arguments", len(args))
+	}
+	if err := json.Unmarshal(args[0], &req.TxHash); err != nil {
+		return err
+	}
+	return json.Unmarshal(args[1], &req.Nout)
+}
+
+func (req *OutpointReq) outpoint() (*wire.OutPoint, error) {
+	txHash, err := chainhash.NewHashFromStr(req.TxHash)
+	if err != nil {
+		return nil, err
+	}
+	if req.Nout > math.MaxUint16 {
+		return nil, fmt.Errorf("invalid txout: %v", req.Nout)
+	}
+	return wire.NewOutPoint(txHash, req.Nout), nil
+}
+
+// OutpointStatusResp is the status of an outpoint. It is empty if the
+// outpoint is unknown. Height is 0 while the funding tx is in the
+// mempool, SpenderHeight is 0 while the spending tx is in the mempool.
+type OutpointStatusResp struct {
+	Height        *uint32 `json:"height,omitempty"`
+	Spent         bool    `json:"spent,omitempty"`
+	SpenderTxHash string  `json:"spender_txhash,omitempty"`
+	SpenderHeight *uint32 `json:"spender_height,omitempty"`
+}
+
+func (status *OutpointStatusResp) equal(other *OutpointStatusResp) bool {
+	a, _ := json.Marshal(status)
+	b, _ := json.Marshal(other)
+	return bytes.Equal(a, b)
+}
+
+// maxSpenderTxs is the most txs of a script's history read to find the
+// confirmed spender of an outpoint.
+const maxSpenderTxs = 1000
+
+// outpointStatus looks up the current status of the outpoint. The
+// previously sent status, if any, saves looking up a confirmed spender
+// again, or looking again after it wasn't found. Mempool spends are looked
+// up in mempoolSpenders.
+func outpointStatus(DB *db.ReadOnlyDBColumnFamily, chain *chaincfg.Params, outpoint *wire.OutPoint, prev *OutpointStatusResp, mempoolSpenders map[wire.OutPoint]chainhash.Hash) (*OutpointStatusResp, error) {
+	dbStatus, err := DB.GetOutpointStatus(&outpoint.Hash, uint16(outpoint.Index), mempoolSpenders)
+	if err != nil {
+		return nil, err
+	}
+	result := &OutpointStatusResp{}
+	if dbStatus == nil {
+		return result, nil
+	}
+	result.Height = &dbStatus.Height
+	if dbStatus.SpenderTxHash != nil {
+		var mempoolHeight uint32 = 0
+		result.Spent = true
+		result.SpenderTxHash = dbStatus.SpenderTxHash.String()
+		result.SpenderHeight = &mempoolHeight
+	} else if dbStatus.Spent {
+		result.Spent = true
+		if prev != nil && prev.Spent && (prev.SpenderHeight == nil || *prev.SpenderHeight > 0) {
+			result.SpenderTxHash = prev.SpenderTxHash
+			result.SpenderHeight = prev.SpenderHeight
+			return result, nil
+		}
+		spender, height, err := outpointSpender(DB, chain, outpoint, dbStatus.Height)
+		if err != nil {
+			return nil, err
+		}
+		if spender != nil {
+			result.SpenderTxHash = spender.String()
+			result.SpenderHeight = &height
+		}
+	}
+	return result, nil
+}
+
+// outpointSpender finds the confirmed tx spending the outpoint, funded at
+// fundingHeight, in the first maxSpenderTxs txs of the history of the
+// script it pays to.
+func outpointSpender(DB *db.ReadOnlyDBColumnFamily, chain *chaincfg.Params, outpoint *wire.OutPoint, fundingHeight uint32) (*chainhash.Hash, uint32, error) {
+	rawTx, err := DB.GetTx(&outpoint.Hash)
+	if err != nil || rawTx == nil {
+		return nil, 0, err
+	}
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return nil, 0, err
+	}
+	if int(outpoint.Index) >= len(tx.TxOut) {
+		return nil, 0, nil
+	}
+	hashX := hashXScript(tx.TxOut[outpoint.Index].PkScript, chain)
+	return DB.GetSpender(outpoint, hashX, fundingHeight, maxSpenderTxs)
+}
+
+// 'blockchain.outpoint.subscribe'
+func (s *BlockchainOutpointService) Subscribe(req *OutpointReq, resp **OutpointStatusResp) error {
+	if s.sessionMgr == nil || s.session == nil {
+		return errors.New("no session, rpc not supported")
+	}
+	outpoint, err := req.outpoint()
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	mempoolSpenders, err := s.sessionMgr.mempoolSpenders(s.sessionMgr.manageInterval)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	status, err := outpointStatus(s.DB, s.Chain, outpoint, s.sessionMgr.outpointLastStatus(outpoint), mempoolSpenders)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	s.sessionMgr.outpointSubscribe(s.session, outpoint, status, true /*subscribe*/)
+	*resp = status
+	return nil
+}
+
+type OutpointUnsubscribeResp bool
+
+// 'blockchain.outpoint.unsubscribe'
+func (s *BlockchainOutpointService) Unsubscribe(req *OutpointReq, resp **OutpointUnsubscribeResp) error {
+	if s.sessionMgr == nil || s.session == nil {
+		return errors.New("no session, rpc not supported")
+	}
+	outpoint, err := req.outpoint()
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	result := OutpointUnsubscribeResp(s.sessionMgr.outpointSubscribe(s.session, outpoint, nil, false /*subscribe*/))
+	*resp = &result
+	return nil
+}
@@ -1,6 +1,7 @@
 package server
 
 import (
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"net"
@@ -10,7 +11,9 @@ import (
 
 	"github.com/lbryio/herald.go/db"
 	"github.com/lbryio/herald.go/internal"
+	pb "github.com/lbryio/herald.go/protobuf/go"
 	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
 	"github.com/lbryio/lbcd/txscript"
 	"github.com/lbryio/lbcutil"
 	"github.com/lbryio/lbry.go/v3/extras/stop"
@@ -332,7 +335,7 @@ func TestGetHistory(t *testing.T) {
 	}
 
 	for _, addr := range regTestAddrs {
-		req := AddressGetHistoryReq{addr}
+		req := AddressGetHistoryReq{Address: addr}
 		var resp *AddressGetHistoryResp
 		err := s.Get_history(&req, &resp)
 		if err != nil {
@@ -376,6 +379,154 @@ func TestListUnspent(t *testing.T) {
 	}
 }
 
+func TestGetBalanceDetailed(t *testing.T) {
+	secondaryPath := "asdf"
+	grp := stop.NewDebug()
+	db, err := db.GetProdDB(regTestDBPath, secondaryPath, grp)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s := &BlockchainAddressService{
+		DB:    db,
+		Chain: &chaincfg.RegressionNetParams,
+	}
+
+	for _, addr := range regTestAddrs {
+		req := AddressGetBalanceReq{addr}
+		var balance *AddressGetBalanceResp
+		err := s.Get_balance(&req, &balance)
+		if err != nil {
+			t.Errorf("address: %v handler err: %v", addr, err)
+			continue
+		}
+		var resp *GetBalanceDetailedResp
+		err = s.Get_balance_detailed(&req, &resp)
+		if err != nil {
+			t.Errorf("address: %v handler err: %v", addr, err)
+			continue
+		}
+		total := resp.Confirmed.Spendable + resp.Confirmed.Claims + resp.Confirmed.Supports
+		if total != balance.Confirmed {
+			t.Errorf("address: %v detailed total %v != confirmed %v", addr, total, balance.Confirmed)
+		}
+		marshalled, err := json.MarshalIndent(resp, "", "    ")
+		if err != nil {
+			t.Errorf("address: %v unmarshal err: %v", addr, err)
+		}
+		t.Logf("address: %v resp: %v", addr, string(marshalled))
+	}
+}
+
+func TestListClaims(t *testing.T) {
+	const supportType = db.TXOTypeSupport
+	secondaryPath := "asdf"
+	grp := stop.NewDebug()
+	db, err := db.GetProdDB(regTestDBPath, secondaryPath, grp)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s := &BlockchainAddressService{
+		DB:    db,
+		Chain: &chaincfg.RegressionNetParams,
+	}
+
+	// hasOutput reports whether outputs include the txo txNum:nout.
+	hasOutput := func(outputs []*pb.Output, txNum uint32, nout uint16) bool {
+		txHash, err := db.GetTxHash(txNum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, output := range outputs {
+			if string(output.TxHash) == string(txHash) && output.Nout == uint32(nout) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var claims, supports int
+	for _, addr := range regTestAddrs {
+		req := AddressListClaimsReq{Address: addr, ClaimtriePageReq: ClaimtriePageReq{Limit: maxClaimtriePageSize}}
+		var resp *pb.Outputs
+		err := s.List_claims(&req, &resp)
+		if err != nil {
+			t.Errorf("address: %v handler err: %v", addr, err)
+			continue
+		}
+		if resp.Total != uint32(len(resp.Txos)) || resp.Offset != 0 {
+			t.Errorf("address: %v total %v offset %v with %v txos", addr, resp.Total, resp.Offset, len(resp.Txos))
+		}
+		for _, txo := range resp.Txos {
+			txHash, _ := chainhash.NewHash(txo.TxHash)
+			txNum, err := db.GetTxNum(txHash)
+			if err != nil || txNum == nil {
+				t.Errorf("address: %v txo %v:%v has no tx num: %v", addr, txHash, txo.Nout, err)
+				continue
+			}
+			info, err := db.GetTXOClaimInfo(txNum.TxNum, uint16(txo.Nout))
+			if err != nil || info == nil {
+				t.Errorf("address: %v txo %v:%v is not a claim or support: %v", addr, txHash, txo.Nout, err)
+				continue
+			}
+			if info.Type != supportType {
+				claims++
+				if txo.GetClaim() == nil {
+					t.Errorf("address: %v claim %v:%v is not resolved", addr, txHash, txo.Nout)
+				}
+				continue
+			}
+			supports++
+			if txo.GetClaim() != nil {
+				t.Errorf("address: %v support %v:%v is resolved as a claim", addr, txHash, txo.Nout)
+			}
+			claimTxo, err := db.GetClaimTxo(info.ClaimHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if claimTxo != nil && !hasOutput(resp.ExtraTxos, claimTxo.TxNum, claimTxo.Position) {
+				t.Errorf("address: %v support %v:%v is missing its claim %x in extra txos",
+					addr, txHash, txo.Nout, info.ClaimHash)
+			}
+		}
+
+		// Page through the claims one at a time.
+		for offset := 0; offset <= len(resp.Txos); offset++ {
+			req := AddressListClaimsReq{Address: addr, ClaimtriePageReq: ClaimtriePageReq{Offset: offset, Limit: 1}}
+			var page *pb.Outputs
+			err := s.List_claims(&req, &page)
+			if err != nil {
+				t.Errorf("address: %v offset %v handler err: %v", addr, offset, err)
+				break
+			}
+			if page.Total != resp.Total || page.Offset != uint32(offset) {
+				t.Errorf("address: %v offset %v got total %v offset %v", addr, offset, page.Total, page.Offset)
+			}
+			if offset == len(resp.Txos) {
+				if len(page.Txos) != 0 || len(page.ExtraTxos) != 0 {
+					t.Errorf("address: %v page past the end has %v txos", addr, len(page.Txos))
+				}
+				break
+			}
+			want := resp.Txos[offset]
+			if len(page.Txos) != 1 || string(page.Txos[0].TxHash) != string(want.TxHash) || page.Txos[0].Nout != want.Nout {
+				t.Errorf("address: %v offset %v got %v want %v:%v", addr, offset, page.Txos, want.TxHash, want.Nout)
+			}
+			if want.GetClaim() == nil && len(page.ExtraTxos) > 1 {
+				t.Errorf("address: %v offset %v support has %v extra txos", addr, offset, len(page.ExtraTxos))
+			}
+		}
+	}
+	if claims == 0 || supports == 0 {
+		t.Errorf("got %v claims and %v supports, want both", claims, supports)
+	}
+}
+
 func TestAddressSubscribe(t *testing.T) {
 	args := MakeDefaultTestArgs()
 	grp := stop.NewDebug()
@@ -466,3 +617,149 @@ func TestAddressSubscribe(t *testing.T) {
 	t.Logf("waiting to receive notification(s)...")
 	received.Wait()
 }
+
+func TestGetStatusMany(t *testing.T) {
+	secondaryPath := "asdf"
+	grp := stop.NewDebug()
+	db, err := db.GetProdDB(regTestDBPath, secondaryPath, grp)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s := &BlockchainScripthashService{
+		DB:    db,
+		Chain: &chaincfg.RegressionNetParams,
+	}
+
+	req := ScripthashManyReq{}
+	hashXs := [][]byte{}
+	for _, addr := range regTestAddrs {
+		address, _ := lbcutil.DecodeAddress(addr, s.Chain)
+		script, _ := txscript.PayToAddrScript(address)
+		scripthash := sha256.Sum256(script)
+		internal.ReverseBytesInPlace(scripthash[:])
+		req = append(req, hex.EncodeToString(scripthash[:]))
+		hashXs = append(hashXs, hashXScript(script, s.Chain))
+	}
+	var resp *ScripthashStatusManyResp
+	err = s.Get_status_many(&req, &resp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if len(*resp) != len(req) {
+		t.Fatalf("expected %v statuses, got %v", len(req), len(*resp))
+	}
+	for i, hashX := range hashXs {
+		status, err := db.GetStatus(hashX)
+		if err != nil {
+			t.Errorf("address: %v GetStatus err: %v", regTestAddrs[i], err)
+		}
+		if (*resp)[i] != hex.EncodeToString(status) {
+			t.Errorf("address: %v status mismatch: %v != %x", regTestAddrs[i], (*resp)[i], status)
+		}
+	}
+}
+
+func TestGetHistoryPaginated(t *testing.T) {
+	secondaryPath := "asdf"
+	grp := stop.NewDebug()
+	db, err := db.GetProdDB(regTestDBPath, secondaryPath, grp)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	s := &BlockchainAddressService{
+		DB:    db,
+		Chain: &chaincfg.RegressionNetParams,
+	}
+
+	for _, addr := range regTestAddrs {
+		req := AddressGetHistoryReq{Address: addr}
+		var full *AddressGetHistoryResp
+		err := s.Get_history(&req, &full)
+		if err != nil {
+			t.Errorf("address: %v handler err: %v", addr, err)
+			continue
+		}
+		paged := make([]TxInfo, 0, len(full.Confirmed))
+		req.Limit = 1
+		for {
+			var resp *AddressGetHistoryResp
+			err := s.Get_history(&req, &resp)
+			if err != nil {
+				t.Errorf("address: %v handler err: %v", addr, err)
+				break
+			}
+			paged = append(paged, resp.Confirmed...)
+			if resp.Cursor == nil {
+				break
+			}
+			req.Cursor = resp.Cursor
+		}
+		if len(paged) != len(full.Confirmed) {
+			t.Errorf("address: %v paged %v txs, unpaginated %v", addr, len(paged), len(full.Confirmed))
+			continue
+		}
+		for i := range paged {
+			if paged[i] != full.Confirmed[i] {
+				t.Errorf("address: %v tx %v mismatch: %v != %v", addr, i, paged[i], full.Confirmed[i])
+			}
+		}
+	}
+}
+
+func TestOutpointSubscribe(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	grp := stop.NewDebug()
+	secondaryPath := "asdf"
+	db, err := db.GetProdDB(regTestDBPath, secondaryPath, grp)
+	defer db.Shutdown()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sm := newSessionManager(db, args, grp, &chaincfg.RegressionNetParams)
+	sm.start()
+	defer sm.stop()
+
+	_, server1 := net.Pipe()
+	sess1 := sm.addSession(server1)
+
+	s := &BlockchainOutpointService{
+		DB:         db,
+		Chain:      &chaincfg.RegressionNetParams,
+		sessionMgr: sm,
+		session:    sess1,
+	}
+
+	address, _ := lbcutil.DecodeAddress(regTestAddrs[0], sm.chain)
+	script, _ := txscript.PayToAddrScript(address)
+	utxos, err := db.GetUnspent(hashXScript(script, sm.chain))
+	if err != nil || len(utxos) == 0 {
+		t.Fatalf("no utxos: %v", err)
+	}
+
+	req := OutpointReq{utxos[0].TxHash.String(), uint32(utxos[0].TxPos)}
+	var resp *OutpointStatusResp
+	err = s.Subscribe(&req, &resp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if resp.Height == nil || *resp.Height != utxos[0].Height || resp.Spent {
+		t.Errorf("unexpected status: %+v", resp)
+	}
+
+	var unsubResp *OutpointUnsubscribeResp
+	err = s.Unsubscribe(&req, &unsubResp)
+	if err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if !*unsubResp {
+		t.Errorf("expected outpoint to be subscribed")
+	}
+}
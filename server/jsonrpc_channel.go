@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/lbryio/herald.go/db"
+	log "github.com/sirupsen/logrus"
+)
+
+// BlockchainChannelService methods handle "blockchain.channel.*" RPCs
+type BlockchainChannelService struct {
+	DB *db.ReadOnlyDBColumnFamily
+}
+
+// maxChannelsPerRequest limits the size of the batch channel methods.
+const maxChannelsPerRequest = 10
+
+type ChannelStatsReq struct {
+	ChannelId string `json:"channel_id"`
+}
+
+type ChannelStatsResp struct {
+	ChannelId       string `json:"channel_id"`
+	ClaimCount      uint32 `json:"claim_count"`
+	SupportAmount   uint64 `json:"support_amount"`
+	EffectiveAmount uint64 `json:"effective_amount"`
+	RepostCount     int    `json:"repost_count"`
+	EarliestHeight  uint32 `json:"earliest_height"`
+	LatestHeight    uint32 `json:"latest_height"`
+	// Error is set in place of the stats of a channel which failed in a
+	// batch.
+	Error string `json:"error,omitempty"`
+}
+
+func channelStats(DB *db.ReadOnlyDBColumnFamily, channelId string) (*ChannelStatsResp, error) {
+	channelHash, err := decodeClaimId(channelId)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := DB.GetChannelStats(channelHash)
+	if err != nil {
+		return nil, err
+	}
+	return &ChannelStatsResp{
+		ChannelId:       hex.EncodeToString(channelHash),
+		ClaimCount:      stats.ClaimCount,
+		SupportAmount:   stats.SupportAmount,
+		EffectiveAmount: stats.EffectiveAmount,
+		RepostCount:     stats.RepostCount,
+		EarliestHeight:  stats.EarliestHeight,
+		LatestHeight:    stats.LatestHeight,
+	}, nil
+}
+
+// 'blockchain.channel.stats'
+func (s *BlockchainChannelService) Stats(req *ChannelStatsReq, resp **ChannelStatsResp) error {
+	result, err := channelStats(s.DB, req.ChannelId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*resp = result
+	return nil
+}
+
+type ChannelStatsManyReq struct {
+	ChannelIds []string `json:"channel_ids"`
+}
+
+// ChannelStatsManyResp holds the stats of each requested channel, in order.
+type ChannelStatsManyResp []*ChannelStatsResp
+
+// 'blockchain.channel.stats_many'
+// A channel which fails has its error in place of its stats, so it doesn't
+// fail the others.
+func (s *BlockchainChannelService) Stats_many(req *ChannelStatsManyReq, resp **ChannelStatsManyResp) error {
+	if len(req.ChannelIds) > maxChannelsPerRequest {
+		err := fmt.Errorf("too many channels: %v (max %v)", len(req.ChannelIds), maxChannelsPerRequest)
+		log.Warn(err)
+		return err
+	}
+	result := make(ChannelStatsManyResp, 0, len(req.ChannelIds))
+	for _, channelId := range req.ChannelIds {
+		stats, err := channelStats(s.DB, channelId)
+		if err != nil {
+			log.Warn(err)
+			stats = &ChannelStatsResp{ChannelId: channelId, Error: err.Error()}
+		}
+		result = append(result, stats)
+	}
+	*resp = &result
+	return nil
+}
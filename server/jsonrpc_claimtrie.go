@@ -1,8 +1,17 @@
 package server
 
 import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/lbryio/herald.go/db"
+	"github.com/lbryio/herald.go/db/stack"
+	"github.com/lbryio/herald.go/internal"
 	pb "github.com/lbryio/herald.go/protobuf/go"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -25,3 +34,937 @@ func (t *ClaimtrieService) Resolve(args *ResolveData, result **pb.Outputs) error
 	*result = res
 	return err
 }
+
+const (
+	defaultClaimtriePageSize = 100
+	maxClaimtriePageSize     = 1000
+)
+
+// ClaimtriePageReq is embedded in requests for paginated listings.
+type ClaimtriePageReq struct {
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// page returns the offset and limit clamped to sane values.
+func (req *ClaimtriePageReq) page() (offset, limit int) {
+	limit = req.Limit
+	if limit <= 0 {
+		limit = defaultClaimtriePageSize
+	}
+	return max(req.Offset, 0), min(limit, maxClaimtriePageSize)
+}
+
+type SupportsReq struct {
+	ClaimId        string `json:"claim_id"`
+	IncludePending bool   `json:"include_pending"`
+	ClaimtriePageReq
+}
+
+type SupportInfo struct {
+	TxHash           string `json:"tx_hash"`
+	Nout             uint16 `json:"nout"`
+	Height           uint32 `json:"height"`
+	Amount           uint64 `json:"amount"`
+	ActivationHeight uint32 `json:"activation_height"`
+}
+
+type SupportsResp struct {
+	Supports []SupportInfo `json:"supports"`
+	Offset   int           `json:"offset"`
+}
+
+// decodeClaimId decodes a full hex claim id into a claim hash.
+func decodeClaimId(claimId string) ([]byte, error) {
+	claimHash, err := hex.DecodeString(claimId)
+	if err != nil {
+		return nil, err
+	}
+	if len(claimHash) != 20 {
+		return nil, errors.New("invalid claim id")
+	}
+	return claimHash, nil
+}
+
+// Supports is the json rpc endpoint for 'blockchain.claimtrie.supports'.
+func (t *ClaimtrieService) Supports(args *SupportsReq, result **SupportsResp) error {
+	claimHash, err := decodeClaimId(args.ClaimId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	offset, limit := args.page()
+	supports, err := t.DB.GetSupports(claimHash, args.IncludePending, offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &SupportsResp{
+		Supports: make([]SupportInfo, 0, len(supports)),
+		Offset:   offset,
+	}
+	for _, support := range supports {
+		res.Supports = append(res.Supports, SupportInfo{
+			TxHash:           support.TxHash.String(),
+			Nout:             support.Position,
+			Height:           support.Height,
+			Amount:           support.Amount,
+			ActivationHeight: support.ActivationHeight,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type ClaimsForNameReq struct {
+	Name string `json:"name"`
+}
+
+type NameClaimInfo struct {
+	ClaimId          string `json:"claim_id"`
+	Name             string `json:"name"`
+	TxHash           string `json:"tx_hash"`
+	Nout             uint16 `json:"nout"`
+	Height           uint32 `json:"height"`
+	EffectiveAmount  uint64 `json:"effective_amount"`
+	ActivationHeight uint32 `json:"activation_height"`
+	IsControlling    bool   `json:"is_controlling"`
+}
+
+type ClaimsForNameResp struct {
+	NormalizedName     string          `json:"normalized_name"`
+	LastTakeoverHeight uint32          `json:"last_takeover_height"`
+	Claims             []NameClaimInfo `json:"claims"`
+}
+
+// Getclaimsforname is the json rpc endpoint for
+// 'blockchain.claimtrie.getclaimsforname'. Claims are returned in bid order.
+func (t *ClaimtrieService) Getclaimsforname(args *ClaimsForNameReq, result **ClaimsForNameResp) error {
+	normalizedName := internal.NormalizeName(args.Name)
+	claims, lastTakeoverHeight, err := t.DB.GetClaimsForName(normalizedName)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &ClaimsForNameResp{
+		NormalizedName:     normalizedName,
+		LastTakeoverHeight: lastTakeoverHeight,
+		Claims:             make([]NameClaimInfo, 0, len(claims)),
+	}
+	for _, claim := range claims {
+		res.Claims = append(res.Claims, NameClaimInfo{
+			ClaimId:          hex.EncodeToString(claim.ClaimHash),
+			Name:             claim.Name,
+			TxHash:           claim.TxHash.String(),
+			Nout:             claim.Position,
+			Height:           claim.Height,
+			EffectiveAmount:  claim.EffectiveAmount,
+			ActivationHeight: claim.ActivationHeight,
+			IsControlling:    claim.IsControlling,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type TakeoverForecastReq struct {
+	Name string `json:"name"`
+}
+
+type PendingActivationInfo struct {
+	ClaimId          string `json:"claim_id"`
+	TxHash           string `json:"tx_hash"`
+	Nout             uint16 `json:"nout"`
+	Type             string `json:"type"`
+	Amount           uint64 `json:"amount"`
+	ActivationHeight uint32 `json:"activation_height"`
+}
+
+type TakeoverForecastResp struct {
+	NormalizedName           string                  `json:"normalized_name"`
+	Height                   uint32                  `json:"height"`
+	ControllingClaimId       string                  `json:"controlling_claim_id,omitempty"`
+	LastTakeoverHeight       uint32                  `json:"last_takeover_height"`
+	NewClaimActivationHeight uint32                  `json:"new_claim_activation_height"`
+	Pending                  []PendingActivationInfo `json:"pending"`
+	TakeoverClaimId          string                  `json:"takeover_claim_id,omitempty"`
+	TakeoverHeight           uint32                  `json:"takeover_height,omitempty"`
+}
+
+// Gettakeoverforecast is the json rpc endpoint for
+// 'blockchain.claimtrie.gettakeoverforecast'. It lists the pending claims
+// and supports for a name and projects the next takeover, if any.
+func (t *ClaimtrieService) Gettakeoverforecast(args *TakeoverForecastReq, result **TakeoverForecastResp) error {
+	normalizedName := internal.NormalizeName(args.Name)
+	forecast, err := t.DB.GetTakeoverForecast(normalizedName)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &TakeoverForecastResp{
+		NormalizedName:           normalizedName,
+		Height:                   forecast.Height,
+		LastTakeoverHeight:       forecast.LastTakeoverHeight,
+		NewClaimActivationHeight: forecast.NewClaimActivationHeight,
+		Pending:                  make([]PendingActivationInfo, 0, len(forecast.Pending)),
+		TakeoverHeight:           forecast.TakeoverHeight,
+	}
+	if forecast.ControllingClaim != nil {
+		res.ControllingClaimId = hex.EncodeToString(forecast.ControllingClaim)
+	}
+	if forecast.TakeoverClaim != nil {
+		res.TakeoverClaimId = hex.EncodeToString(forecast.TakeoverClaim)
+	}
+	for _, pending := range forecast.Pending {
+		rawTxHash, err := t.DB.GetTxHash(pending.TxNum)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		typ := db.TXOTypeClaim
+		if pending.IsSupport {
+			typ = db.TXOTypeSupport
+		}
+		res.Pending = append(res.Pending, PendingActivationInfo{
+			ClaimId:          hex.EncodeToString(pending.ClaimHash),
+			TxHash:           txHash.String(),
+			Nout:             pending.Position,
+			Type:             typ,
+			Amount:           pending.Amount,
+			ActivationHeight: pending.ActivationHeight,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type ExpiringClaimsReq struct {
+	FromHeight uint32 `json:"from_height"`
+	ToHeight   uint32 `json:"to_height"`
+	ClaimtriePageReq
+}
+
+type ExpiringClaimInfo struct {
+	ClaimId          string `json:"claim_id"`
+	NormalizedName   string `json:"normalized_name"`
+	TxHash           string `json:"tx_hash"`
+	Nout             uint16 `json:"nout"`
+	ExpirationHeight uint32 `json:"expiration_height"`
+}
+
+type ExpiringClaimsResp struct {
+	Claims []ExpiringClaimInfo `json:"claims"`
+	Offset int                 `json:"offset"`
+}
+
+// Getexpiringclaims is the json rpc endpoint for
+// 'blockchain.claimtrie.getexpiringclaims'. It lists the claims expiring
+// between from_height and to_height inclusive.
+func (t *ClaimtrieService) Getexpiringclaims(args *ExpiringClaimsReq, result **ExpiringClaimsResp) error {
+	if args.ToHeight < args.FromHeight {
+		err := errors.New("to_height must not be below from_height")
+		log.Warn(err)
+		return err
+	}
+	offset, limit := args.page()
+	claims, err := t.DB.GetClaimsExpiring(args.FromHeight, args.ToHeight, offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &ExpiringClaimsResp{
+		Claims: make([]ExpiringClaimInfo, 0, len(claims)),
+		Offset: offset,
+	}
+	for _, claim := range claims {
+		rawTxHash, err := t.DB.GetTxHash(claim.TxNum)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		res.Claims = append(res.Claims, ExpiringClaimInfo{
+			ClaimId:          hex.EncodeToString(claim.ClaimHash),
+			NormalizedName:   claim.NormalizedName,
+			TxHash:           txHash.String(),
+			Nout:             claim.Position,
+			ExpirationHeight: claim.ExpirationHeight,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type ClaimExpirationReq struct {
+	ClaimId string `json:"claim_id"`
+}
+
+type ClaimExpirationResp struct {
+	ClaimId          string `json:"claim_id"`
+	Name             string `json:"name"`
+	Height           uint32 `json:"height"`
+	ExpirationHeight uint32 `json:"expiration_height"`
+	Expired          bool   `json:"expired"`
+	BlocksRemaining  uint32 `json:"blocks_remaining"`
+}
+
+// Getclaimexpiration is the json rpc endpoint for
+// 'blockchain.claimtrie.getclaimexpiration'. Height is the height of the
+// last update of the claim.
+func (t *ClaimtrieService) Getclaimexpiration(args *ClaimExpirationReq, result **ClaimExpirationResp) error {
+	claimHash, err := decodeClaimId(args.ClaimId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	claimTxo, err := t.DB.GetClaimTxo(claimHash)
+	if err != nil {
+		log.Warn(err)
+		return err
+	} else if claimTxo == nil {
+		return errors.New("claim not found")
+	}
+	height := t.DB.Height
+	if t.DB.LastState != nil {
+		height = t.DB.LastState.Height
+	}
+	claimHeight := stack.BisectRight(t.DB.TxCounts, []uint32{claimTxo.TxNum})[0]
+	res := &ClaimExpirationResp{
+		ClaimId:          args.ClaimId,
+		Name:             claimTxo.Name,
+		Height:           claimHeight,
+		ExpirationHeight: db.GetClaimExpirationHeight(claimHeight),
+	}
+	if res.ExpirationHeight <= height {
+		res.Expired = true
+	} else {
+		res.BlocksRemaining = res.ExpirationHeight - height
+	}
+	*result = res
+	return nil
+}
+
+type RepostsReq struct {
+	ClaimId string `json:"claim_id"`
+	ClaimtriePageReq
+}
+
+type RepostInfo struct {
+	ClaimId   string `json:"claim_id"`
+	TxHash    string `json:"tx_hash"`
+	Nout      uint16 `json:"nout"`
+	Height    uint32 `json:"height"`
+	ChannelId string `json:"channel_id,omitempty"`
+}
+
+type RepostsResp struct {
+	Reposts []RepostInfo `json:"reposts"`
+	Offset  int          `json:"offset"`
+}
+
+// Getreposts is the json rpc endpoint for 'blockchain.claimtrie.getreposts'.
+// It lists the claims reposting a claim, with their channels.
+func (t *ClaimtrieService) Getreposts(args *RepostsReq, result **RepostsResp) error {
+	claimHash, err := decodeClaimId(args.ClaimId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	offset, limit := args.page()
+	reposts, err := t.DB.GetReposts(claimHash, offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &RepostsResp{
+		Reposts: make([]RepostInfo, 0, len(reposts)),
+		Offset:  offset,
+	}
+	for _, repost := range reposts {
+		rawTxHash, err := t.DB.GetTxHash(repost.TxNum)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		info := RepostInfo{
+			ClaimId: hex.EncodeToString(repost.ClaimHash),
+			TxHash:  txHash.String(),
+			Nout:    repost.Position,
+			Height:  repost.Height,
+		}
+		if repost.ChannelHash != nil {
+			info.ChannelId = hex.EncodeToString(repost.ChannelHash)
+		}
+		res.Reposts = append(res.Reposts, info)
+	}
+	*result = res
+	return nil
+}
+
+const maxRepostChainDepth = 100
+
+type RepostChainReq struct {
+	ClaimId  string `json:"claim_id"`
+	MaxDepth int    `json:"max_depth"`
+}
+
+type RepostChainResp struct {
+	Chain []string `json:"chain"`
+	Cycle bool     `json:"cycle"`
+}
+
+// Getrepostchain is the json rpc endpoint for
+// 'blockchain.claimtrie.getrepostchain'. It follows a repost through any
+// reposts of reposts to the original claim.
+func (t *ClaimtrieService) Getrepostchain(args *RepostChainReq, result **RepostChainResp) error {
+	claimHash, err := decodeClaimId(args.ClaimId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	maxDepth := args.MaxDepth
+	if maxDepth <= 0 || maxDepth > maxRepostChainDepth {
+		maxDepth = maxRepostChainDepth
+	}
+	chain, cycle, err := t.DB.GetRepostChain(claimHash, maxDepth)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &RepostChainResp{
+		Chain: make([]string, 0, len(chain)),
+		Cycle: cycle,
+	}
+	for _, claimHash := range chain {
+		res.Chain = append(res.Chain, hex.EncodeToString(claimHash))
+	}
+	*result = res
+	return nil
+}
+
+type ChannelClaimsReq struct {
+	ChannelId string `json:"channel_id"`
+	OrderBy   string `json:"order_by"`
+	Reverse   bool   `json:"reverse"`
+	ClaimtriePageReq
+}
+
+// channelClaims returns a page of the resolved claims in a channel. Total
+// is the number of claims in the channel.
+func channelClaims(DB *db.ReadOnlyDBColumnFamily, req *ChannelClaimsReq) (*pb.Outputs, error) {
+	channelHash, err := decodeClaimId(req.ChannelId)
+	if err != nil {
+		return nil, err
+	}
+	orderBy := req.OrderBy
+	if orderBy == "" {
+		orderBy = db.ChannelOrderName
+	}
+	offset, limit := req.page()
+	claims, total, err := DB.GetClaimsInChannel(channelHash, orderBy, req.Reverse, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	txos := make([]*pb.Output, 0, len(claims))
+	for _, claim := range claims {
+		txos = append(txos, claim.ToOutput())
+	}
+	return &pb.Outputs{
+		Txos:   txos,
+		Total:  uint32(total),
+		Offset: uint32(offset),
+	}, nil
+}
+
+// Getchannelclaims is the json rpc endpoint for
+// 'blockchain.claimtrie.getchannelclaims'. Claims are ordered by "name"
+// (the default) or "height".
+func (t *ClaimtrieService) Getchannelclaims(args *ChannelClaimsReq, result **pb.Outputs) error {
+	res, err := channelClaims(t.DB, args)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*result = res
+	return nil
+}
+
+type TrendingReq struct {
+	Limit int `json:"limit"`
+}
+
+// trendingClaims returns the resolved top trending claims, highest first.
+// Abandoned claims are skipped.
+func trendingClaims(DB *db.ReadOnlyDBColumnFamily, limit int) (*pb.Outputs, error) {
+	if limit <= 0 {
+		limit = defaultClaimtriePageSize
+	}
+	limit = min(limit, maxClaimtriePageSize)
+	txos := make([]*pb.Output, 0, limit)
+	for _, claim := range DB.Trending.Top(limit) {
+		claimTxo, err := DB.GetClaimTxo(claim.ClaimHash)
+		if err != nil {
+			return nil, err
+		} else if claimTxo == nil {
+			continue
+		}
+		res, err := DB.FsGetClaimByHash(claim.ClaimHash)
+		if err != nil {
+			return nil, err
+		}
+		txos = append(txos, res.ToOutput())
+	}
+	return &pb.Outputs{
+		Txos:  txos,
+		Total: uint32(len(txos)),
+	}, nil
+}
+
+// Trending is the json rpc endpoint for 'blockchain.claimtrie.trending'.
+// It returns the claims with the highest fast_ar trending scores.
+func (t *ClaimtrieService) Trending(args *TrendingReq, result **pb.Outputs) error {
+	res, err := trendingClaims(t.DB, args.Limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*result = res
+	return nil
+}
+
+type VerifyClaimSignatureReq struct {
+	ClaimId string `json:"claim_id"`
+}
+
+type VerifyClaimSignatureResp struct {
+	ClaimId          string `json:"claim_id"`
+	IsSigned         bool   `json:"is_signed"`
+	SigningChannelId string `json:"signing_channel_id,omitempty"`
+	IsSignatureValid bool   `json:"is_signature_valid"`
+}
+
+// Verify_claim_signature is the json rpc endpoint for
+// 'blockchain.claimtrie.verify_claim_signature'. It checks the signature of
+// the current version of a claim against its channel's public key.
+func (t *ClaimtrieService) Verify_claim_signature(args *VerifyClaimSignatureReq, result **VerifyClaimSignatureResp) error {
+	claimHash, err := decodeClaimId(args.ClaimId)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	claimTxo, err := t.DB.GetClaimTxo(claimHash)
+	if err != nil {
+		log.Warn(err)
+		return err
+	} else if claimTxo == nil {
+		return errors.New("claim not found")
+	}
+	signature, err := t.DB.GetClaimSignature(claimHash, claimTxo.TxNum, claimTxo.Position)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &VerifyClaimSignatureResp{ClaimId: args.ClaimId}
+	if signature != nil {
+		res.IsSigned = true
+		res.SigningChannelId = hex.EncodeToString(signature.ChannelHash)
+		res.IsSignatureValid = signature.Valid
+	}
+	*result = res
+	return nil
+}
+
+// maxClaimDiffBlocks is the most blocks returned by one claim diff request,
+// and how far back a claim diff stream remembers the blocks it sent.
+const maxClaimDiffBlocks = 1000
+
+// maxClaimDiffResolves is the most touched claims resolved for one claim
+// diff request.
+const maxClaimDiffResolves = 1000
+
+// claimDiffs returns the claim diffs of the blocks fromHeight through
+// toHeight. If resolve is set the touched claims which still exist are
+// resolved, as of the tip, and the diffs stop before the block which would
+// take them past maxClaimDiffResolves claims. The first block is always
+// returned.
+func claimDiffs(DB *db.ReadOnlyDBColumnFamily, fromHeight, toHeight uint32, resolve bool) ([]*pb.ClaimDiff, error) {
+	diffs, err := DB.GetClaimDiffs(fromHeight, toHeight)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*pb.ClaimDiff, 0, len(diffs))
+	resolved := 0
+	for _, diff := range diffs {
+		if resolve && len(res) > 0 && resolved+len(diff.TouchedClaims) > maxClaimDiffResolves {
+			break
+		}
+		resolved += len(diff.TouchedClaims)
+		claimDiff := &pb.ClaimDiff{
+			Height:        diff.Height,
+			BlockHash:     diff.BlockHash,
+			TouchedClaims: diff.TouchedClaims,
+			DeletedClaims: diff.DeletedClaims,
+		}
+		if resolve {
+			txos := make([]*pb.Output, 0, len(diff.TouchedClaims))
+			for _, claimHash := range diff.TouchedClaims {
+				claimTxo, err := DB.GetClaimTxo(claimHash)
+				if err != nil {
+					return nil, err
+				} else if claimTxo == nil {
+					continue
+				}
+				claim, err := DB.FsGetClaimByHash(claimHash)
+				if err != nil {
+					return nil, err
+				}
+				txos = append(txos, claim.ToOutput())
+			}
+			claimDiff.Outputs = &pb.Outputs{
+				Txos:  txos,
+				Total: uint32(len(txos)),
+			}
+		}
+		res = append(res, claimDiff)
+	}
+	return res, nil
+}
+
+// disconnectedHeight walks back from next through the block hashes a client
+// knows, and returns the height of the first one no longer in the chain.
+// Reorg is false if the block before next is still in the chain.
+func disconnectedHeight(DB *db.ReadOnlyDBColumnFamily, hashes map[uint32][]byte, next uint32) (height uint32, reorg bool, err error) {
+	height = next
+	for height > 0 {
+		known, ok := hashes[height-1]
+		if !ok {
+			break
+		}
+		hash, err := DB.GetBlockHash(height - 1)
+		if err != nil {
+			return 0, false, err
+		}
+		if bytes.Equal(hash, known) {
+			break
+		}
+		height--
+	}
+	return height, height < next, nil
+}
+
+type ClaimDiffsReq struct {
+	FromHeight uint32 `json:"from_height"`
+	ToHeight   uint32 `json:"to_height"`
+	Resolve    bool   `json:"resolve"`
+	// BlockHash is the hash of the block before from_height known to the
+	// client.
+	BlockHash string `json:"block_hash"`
+}
+
+type ClaimDiffInfo struct {
+	Height        uint32      `json:"height"`
+	BlockHash     string      `json:"block_hash,omitempty"`
+	TouchedClaims []string    `json:"touched_claims,omitempty"`
+	DeletedClaims []string    `json:"deleted_claims,omitempty"`
+	Outputs       *pb.Outputs `json:"outputs,omitempty"`
+	Reorg         bool        `json:"reorg,omitempty"`
+}
+
+type ClaimDiffsResp struct {
+	Diffs []ClaimDiffInfo `json:"diffs"`
+}
+
+func claimIds(claimHashes [][]byte) []string {
+	res := make([]string, 0, len(claimHashes))
+	for _, claimHash := range claimHashes {
+		res = append(res, hex.EncodeToString(claimHash))
+	}
+	return res
+}
+
+// Getclaimdiffs is the json rpc endpoint for
+// 'blockchain.claimtrie.getclaimdiffs'. It returns the claims touched and
+// deleted by each block from from_height through to_height, or the tip if
+// to_height is 0. Fewer blocks are returned if resolving them would take
+// too many claims, the client asks again from the block after the last one.
+// If block_hash is no longer in the chain only a reorg marker is returned,
+// with the height of the first disconnected block, and the client must roll
+// back that block and the ones after it before asking again.
+func (t *ClaimtrieService) Getclaimdiffs(args *ClaimDiffsReq, result **ClaimDiffsResp) error {
+	toHeight := args.FromHeight + maxClaimDiffBlocks - 1
+	if args.ToHeight != 0 {
+		if args.ToHeight < args.FromHeight {
+			return errors.New("to_height is before from_height")
+		}
+		toHeight = min(toHeight, args.ToHeight)
+	}
+	res := &ClaimDiffsResp{Diffs: []ClaimDiffInfo{}}
+
+	if args.BlockHash != "" && args.FromHeight > 0 {
+		blockHash, err := chainhash.NewHashFromStr(args.BlockHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		hashes := map[uint32][]byte{args.FromHeight - 1: blockHash[:]}
+		height, reorg, err := disconnectedHeight(t.DB, hashes, args.FromHeight)
+		if err != nil {
+			log.Warn(err)
+			return err
+		} else if reorg {
+			res.Diffs = append(res.Diffs, ClaimDiffInfo{Height: height, Reorg: true})
+			*result = res
+			return nil
+		}
+	}
+
+	diffs, err := claimDiffs(t.DB, args.FromHeight, toHeight, args.Resolve)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	for _, diff := range diffs {
+		blockHash, err := chainhash.NewHash(diff.BlockHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		res.Diffs = append(res.Diffs, ClaimDiffInfo{
+			Height:        diff.Height,
+			BlockHash:     blockHash.String(),
+			TouchedClaims: claimIds(diff.TouchedClaims),
+			DeletedClaims: claimIds(diff.DeletedClaims),
+			Outputs:       diff.Outputs,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type OutpointClaimReq struct {
+	TxHash string `json:"tx_hash"`
+	Nout   uint16 `json:"nout"`
+}
+
+type OutpointClaimResp struct {
+	ClaimId   string `json:"claim_id"`
+	Type      string `json:"type"`
+	IsCurrent bool   `json:"is_current"`
+	// Claim is the resolved claim, unless it was abandoned.
+	Claim *pb.Output `json:"claim,omitempty"`
+}
+
+// Getclaimbyoutpoint is the json rpc endpoint for
+// 'blockchain.claimtrie.getclaimbyoutpoint'. It returns the claim an
+// outpoint creates, updates or supports, and whether the outpoint is the
+// claim's current TXO, an old version of it, or a support.
+func (t *ClaimtrieService) Getclaimbyoutpoint(args *OutpointClaimReq, result **OutpointClaimResp) error {
+	txHash, err := chainhash.NewHashFromStr(args.TxHash)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	claim, err := t.DB.GetOutpointClaim(txHash, args.Nout)
+	if err != nil {
+		log.Warn(err)
+		return err
+	} else if claim == nil {
+		return errors.New("outpoint is not a claim or support")
+	}
+	res := &OutpointClaimResp{
+		ClaimId:   hex.EncodeToString(claim.ClaimHash),
+		Type:      claim.Type,
+		IsCurrent: claim.Current,
+	}
+	claimTxo, err := t.DB.GetClaimTxo(claim.ClaimHash)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	if claimTxo != nil {
+		resolved, err := t.DB.FsGetClaimByHash(claim.ClaimHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		res.Claim = resolved.ToOutput()
+	}
+	*result = res
+	return nil
+}
+
+type ClaimsByShortIdReq struct {
+	Name string `json:"name"`
+	// ClaimId is a prefix of the claim id, as in lbry://name#ab.
+	ClaimId string `json:"claim_id"`
+	ClaimtriePageReq
+}
+
+type ShortIdClaimInfo struct {
+	ClaimId          string `json:"claim_id"`
+	Name             string `json:"name"`
+	TxHash           string `json:"tx_hash"`
+	Nout             uint16 `json:"nout"`
+	ShortUrl         string `json:"short_url"`
+	ActivationHeight uint32 `json:"activation_height"`
+}
+
+type ClaimsByShortIdResp struct {
+	NormalizedName string             `json:"normalized_name"`
+	Claims         []ShortIdClaimInfo `json:"claims"`
+	Offset         int                `json:"offset"`
+}
+
+// Getclaimsbyshortid is the json rpc endpoint for
+// 'blockchain.claimtrie.getclaimsbyshortid'. It lists the claims for a name
+// whose claim id starts with claim_id, oldest first, with the shortest
+// url which resolves to each of them.
+func (t *ClaimtrieService) Getclaimsbyshortid(args *ClaimsByShortIdReq, result **ClaimsByShortIdResp) error {
+	partialClaimId := strings.ToLower(args.ClaimId)
+	if len(partialClaimId) == 0 || len(partialClaimId) > 40 ||
+		strings.Trim(partialClaimId, "0123456789abcdef") != "" {
+		return errors.New("invalid claim id prefix")
+	}
+	normalizedName := internal.NormalizeName(args.Name)
+	offset, limit := args.page()
+	claims, err := t.DB.GetClaimsByShortId(normalizedName, partialClaimId, offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &ClaimsByShortIdResp{
+		NormalizedName: normalizedName,
+		Claims:         make([]ShortIdClaimInfo, 0, len(claims)),
+		Offset:         offset,
+	}
+	for _, claim := range claims {
+		rawTxHash, err := t.DB.GetTxHash(claim.TxNum)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		txHash, err := chainhash.NewHash(rawTxHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		res.Claims = append(res.Claims, ShortIdClaimInfo{
+			ClaimId:          hex.EncodeToString(claim.ClaimHash),
+			Name:             claim.Name,
+			TxHash:           txHash.String(),
+			Nout:             claim.Position,
+			ShortUrl:         claim.ShortUrl,
+			ActivationHeight: claim.ActivationHeight,
+		})
+	}
+	*result = res
+	return nil
+}
+
+// maxNamesPageSize is the most names returned by getnamesbyprefix.
+const maxNamesPageSize = 100
+
+type NamesByPrefixReq struct {
+	Prefix string `json:"prefix"`
+	// Cursor is the last name of the previous page.
+	Cursor string `json:"cursor"`
+	Limit  int    `json:"limit"`
+}
+
+type NameTakeoverInfo struct {
+	NormalizedName     string `json:"normalized_name"`
+	ClaimId            string `json:"claim_id"`
+	LastTakeoverHeight uint32 `json:"last_takeover_height"`
+}
+
+type NamesByPrefixResp struct {
+	Names []NameTakeoverInfo `json:"names"`
+	// Cursor is set if there are more names.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// Getnamesbyprefix is the json rpc endpoint for
+// 'blockchain.claimtrie.getnamesbyprefix'. It lists the names starting with
+// prefix in byte order, with their controlling claims.
+func (t *ClaimtrieService) Getnamesbyprefix(args *NamesByPrefixReq, result **NamesByPrefixResp) error {
+	limit := args.Limit
+	if limit <= 0 || limit > maxNamesPageSize {
+		limit = maxNamesPageSize
+	}
+	// Read one more name to tell if there is another page.
+	names, err := t.DB.GetNamesByPrefix(internal.NormalizeName(args.Prefix), args.Cursor, limit+1)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	res := &NamesByPrefixResp{
+		Names: make([]NameTakeoverInfo, 0, len(names)),
+	}
+	if len(names) > limit {
+		names = names[:limit]
+		res.Cursor = names[limit-1].NormalizedName
+	}
+	for _, name := range names {
+		res.Names = append(res.Names, NameTakeoverInfo{
+			NormalizedName:     name.NormalizedName,
+			ClaimId:            hex.EncodeToString(name.ClaimHash),
+			LastTakeoverHeight: name.LastTakeoverHeight,
+		})
+	}
+	*result = res
+	return nil
+}
+
+type TopStakedReq struct {
+	// ClaimType is one of "stream", "channel", "repost" or "collection".
+	ClaimType string `json:"claim_type"`
+	MinHeight uint32 `json:"min_height"`
+	ClaimtriePageReq
+}
+
+// Topstaked is the json rpc endpoint for 'blockchain.claimtrie.topstaked'.
+// It returns the resolved claims with the highest effective amounts. Only
+// the db.MaxTopStakedClaims highest staked claims are ranked, the filters
+// apply within them and pages can't go past them.
+func (t *ClaimtrieService) Topstaked(args *TopStakedReq, result **pb.Outputs) error {
+	switch args.ClaimType {
+	case "", db.ClaimTypeStream, db.ClaimTypeChannel, db.ClaimTypeRepost, db.ClaimTypeCollection:
+	default:
+		return fmt.Errorf("invalid claim type: %s", args.ClaimType)
+	}
+	offset, limit := args.page()
+	claims, err := t.DB.GetTopStakedClaims(args.ClaimType, args.MinHeight, offset, limit)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	txos := make([]*pb.Output, 0, len(claims))
+	for _, claim := range claims {
+		res, err := t.DB.FsGetClaimByHash(claim.ClaimHash)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+		txos = append(txos, res.ToOutput())
+	}
+	*result = &pb.Outputs{
+		Txos:   txos,
+		Total:  uint32(len(txos)),
+		Offset: uint32(offset),
+	}
+	return nil
+}
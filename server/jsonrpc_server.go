@@ -1,11 +1,27 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lbryio/herald.go/db"
+	"github.com/lbryio/lbcd/chaincfg"
 	log "github.com/sirupsen/logrus"
 )
 
 type ServerService struct {
-	Args *Args
+	Args  *Args
+	Chain *chaincfg.Params
+	// needed for federation peers
+	server *Server
+	// needed for per-session state
+	sessionMgr *sessionManager
+	session    *session
 }
 
 type ServerFeatureService struct {
@@ -14,40 +30,79 @@ type ServerFeatureService struct {
 
 type ServerFeaturesReq struct{}
 
+// ServerFeaturesHost lists the ports a host serves on. Ports
+// which are not served are null.
+type ServerFeaturesHost struct {
+	TcpPort  *int `json:"tcp_port"`
+	SslPort  *int `json:"ssl_port"`
+	WsPort   *int `json:"ws_port,omitempty"`
+	HttpPort *int `json:"http_port,omitempty"`
+}
+
 type ServerFeaturesRes struct {
-	Hosts             map[string]string `json:"hosts"`
-	Pruning           string            `json:"pruning"`
-	ServerVersion     string            `json:"server_version"`
-	ProtocolMin       string            `json:"protocol_min"`
-	ProtocolMax       string            `json:"protocol_max"`
-	GenesisHash       string            `json:"genesis_hash"`
-	Description       string            `json:"description"`
-	PaymentAddress    string            `json:"payment_address"`
-	DonationAddress   string            `json:"donation_address"`
-	DailyFee          string            `json:"daily_fee"`
-	HashFunction      string            `json:"hash_function"`
-	TrendingAlgorithm string            `json:"trending_algorithm"`
+	Hosts             map[string]ServerFeaturesHost `json:"hosts"`
+	Pruning           string                        `json:"pruning"`
+	ServerVersion     string                        `json:"server_version"`
+	ProtocolMin       string                        `json:"protocol_min"`
+	ProtocolMax       string                        `json:"protocol_max"`
+	GenesisHash       string                        `json:"genesis_hash"`
+	Description       string                        `json:"description"`
+	PaymentAddress    string                        `json:"payment_address"`
+	DonationAddress   string                        `json:"donation_address"`
+	DailyFee          string                        `json:"daily_fee"`
+	HashFunction      string                        `json:"hash_function"`
+	TrendingAlgorithm string                        `json:"trending_algorithm"`
 }
 
-// Features is the json rpc endpoint for 'server.features'.
-func (t *ServerService) Features(req *ServerFeaturesReq, res **ServerFeaturesRes) error {
-	log.Println("Features")
+// genesisHash returns the genesis hash of the chain we serve,
+// falling back to the configured one if the chain is unknown.
+func genesisHash(args *Args, chain *chaincfg.Params) string {
+	if chain != nil && chain.GenesisHash != nil {
+		return chain.GenesisHash.String()
+	}
+	return args.GenesisHash
+}
+
+// makeServerFeatures builds the features shared by the json rpc
+// 'server.features' and grpc Features endpoints.
+func makeServerFeatures(args *Args, chain *chaincfg.Params) *ServerFeaturesRes {
+	portOrNil := func(port int) *int {
+		if port == 0 {
+			return nil
+		}
+		return &port
+	}
+	hosts := make(map[string]ServerFeaturesHost, len(args.PublicHostnames))
+	for _, hostname := range args.PublicHostnames {
+		hosts[hostname] = ServerFeaturesHost{
+			TcpPort:  portOrNil(args.JSONRPCPort),
+			SslPort:  portOrNil(args.PublicSSLPort),
+			WsPort:   portOrNil(args.PublicWSPort),
+			HttpPort: portOrNil(args.JSONRPCHTTPPort),
+		}
+	}
 
-	features := &ServerFeaturesRes{
-		Hosts:             map[string]string{},
+	return &ServerFeaturesRes{
+		Hosts:             hosts,
 		Pruning:           "",
-		ServerVersion:     HUB_PROTOCOL_VERSION,
-		ProtocolMin:       PROTOCOL_MIN,
-		ProtocolMax:       PROTOCOL_MAX,
-		GenesisHash:       t.Args.GenesisHash,
-		Description:       t.Args.ServerDescription,
-		PaymentAddress:    t.Args.PaymentAddress,
-		DonationAddress:   t.Args.DonationAddress,
-		DailyFee:          t.Args.DailyFee,
+		ServerVersion:     args.ServerVersion,
+		ProtocolMin:       args.ProtocolMin,
+		ProtocolMax:       args.ProtocolMax,
+		GenesisHash:       genesisHash(args, chain),
+		Description:       args.ServerDescription,
+		PaymentAddress:    args.PaymentAddress,
+		DonationAddress:   args.DonationAddress,
+		DailyFee:          args.DailyFee,
 		HashFunction:      "sha256",
 		TrendingAlgorithm: "fast_ar",
 	}
-	*res = features
+}
+
+// Features is the json rpc endpoint for 'server.features'.
+func (t *ServerService) Features(req *ServerFeaturesReq, res **ServerFeaturesRes) error {
+	log.Println("Features")
+
+	*res = makeServerFeatures(t.Args, t.Chain)
 
 	return nil
 }
@@ -73,16 +128,321 @@ type ServerVersionService struct {
 	Args *Args
 }
 
-type ServerVersionReq [2]string // [client_name, client_version]
+// ServerVersionReq holds the arguments of 'server.version':
+// [client_name, protocol_version] where protocol_version is
+// either a single version string or a [min, max] pair.
+type ServerVersionReq struct {
+	ClientName  string
+	ProtocolMin string
+	ProtocolMax string
+}
+
+func (req *ServerVersionReq) UnmarshalJSON(b []byte) error {
+	// A lone client name is allowed.
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		req.ClientName = name
+		return nil
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal(b, &args); err != nil {
+		return err
+	}
+	if len(args) > 2 {
+		return fmt.Errorf("server.version: too many arguments (%v)", len(args))
+	}
+	if len(args) > 0 {
+		if err := json.Unmarshal(args[0], &req.ClientName); err != nil {
+			return fmt.Errorf("server.version: bad client name: %v", err)
+		}
+	}
+	if len(args) < 2 {
+		return nil
+	}
+	var ver string
+	if err := json.Unmarshal(args[1], &ver); err == nil {
+		req.ProtocolMin, req.ProtocolMax = ver, ver
+		return nil
+	}
+	var vers []string
+	if err := json.Unmarshal(args[1], &vers); err != nil || len(vers) < 1 || len(vers) > 2 {
+		return fmt.Errorf("server.version: bad protocol version: %v", string(args[1]))
+	}
+	req.ProtocolMin, req.ProtocolMax = vers[0], vers[len(vers)-1]
+	return nil
+}
 
 type ServerVersionRes [2]string // [version, protocol_version]
 
+// splitClientName splits the client name sent with 'server.version' into
+// the client software and its version, as in "electrum/4.1.5". The SDK
+// sends its bare version, like "0.113.0".
+func splitClientName(clientName string) (name, version string) {
+	if _, err := parseProtoVersion(clientName); err == nil {
+		return "", clientName
+	}
+	if i := strings.LastIndexAny(clientName, "/ "); i >= 0 {
+		if _, err := parseProtoVersion(clientName[i+1:]); err == nil {
+			return clientName[:i], clientName[i+1:]
+		}
+	}
+	return clientName, ""
+}
+
+// protoVersion is a parsed dotted protocol version like "0.107.0".
+type protoVersion []int
+
+func parseProtoVersion(s string) (protoVersion, error) {
+	parts := strings.Split(s, ".")
+	ver := make(protoVersion, 0, len(parts))
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid protocol version: %q", s)
+		}
+		ver = append(ver, n)
+	}
+	return ver, nil
+}
+
+// compare returns -1, 0 or 1 if v is less than, equal to or greater
+// than other. Missing trailing components are treated as zero.
+func (v protoVersion) compare(other protoVersion) int {
+	for i := 0; i < len(v) || i < len(other); i++ {
+		a, b := 0, 0
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v protoVersion) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+// negotiateProtocol picks the highest protocol version supported by
+// both sides. If the client does not state a version, the server
+// minimum is assumed.
+func negotiateProtocol(clientMin, clientMax, serverMin, serverMax string) (protoVersion, error) {
+	sMin, err := parseProtoVersion(serverMin)
+	if err != nil {
+		return nil, err
+	}
+	sMax, err := parseProtoVersion(serverMax)
+	if err != nil {
+		return nil, err
+	}
+	cMin, cMax := sMin, sMin
+	if clientMin != "" {
+		cMin, err = parseProtoVersion(clientMin)
+		if err != nil {
+			return nil, err
+		}
+		cMax, err = parseProtoVersion(clientMax)
+		if err != nil {
+			return nil, err
+		}
+	}
+	result := cMax
+	if sMax.compare(result) < 0 {
+		result = sMax
+	}
+	if result.compare(cMin) < 0 || result.compare(sMin) < 0 {
+		return nil, fmt.Errorf("unsupported protocol version: %v (server supports %v-%v)",
+			clientMax, serverMin, serverMax)
+	}
+	return result, nil
+}
+
 // Version is the json rpc endpoint for 'server.version'.
 func (t *ServerService) Version(req *ServerVersionReq, res **ServerVersionRes) error {
-	// FIXME: We may need to do the computation of a negotiated version here.
-	// Also return an error if client is not supported?
-	result := [2]string{t.Args.ServerVersion, t.Args.ServerVersion}
+	negotiated, err := negotiateProtocol(req.ProtocolMin, req.ProtocolMax, t.Args.ProtocolMin, t.Args.ProtocolMax)
+	if err != nil {
+		log.Warn(err)
+		if t.session != nil {
+			// Incompatible clients are disconnected once they
+			// receive the error.
+			atomic.StoreInt32(&t.session.closeAfterResponse, 1)
+		}
+		return err
+	}
+	if t.sessionMgr != nil && t.session != nil {
+		clientName, clientVersion := splitClientName(req.ClientName)
+		err = t.sessionMgr.setClientVersion(t.session, clientName, clientVersion, negotiated)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+	}
+	result := [2]string{t.Args.ServerVersion, negotiated.String()}
 	*res = (*ServerVersionRes)(&result)
-	log.Printf("Version(%v) -> %v", *req, **res)
+	log.Printf("Version(%+v) -> %v", *req, **res)
+	return nil
+}
+
+type ServerPingReq struct{}
+
+// Ping is the json rpc endpoint for 'server.ping'.
+func (t *ServerService) Ping(req *ServerPingReq, res *interface{}) error {
+	*res = nil
+	return nil
+}
+
+type ServerStatsReq struct{}
+
+type ServerStatsRes struct {
+	Height      uint32 `json:"height"`
+	Tip         string `json:"tip"`
+	TxCount     uint32 `json:"tx_count"`
+	Claims      int64  `json:"claims"`
+	Supports    int64  `json:"supports"`
+	ActiveNames int64  `json:"active_names"`
+	TotalStaked int64  `json:"total_staked"`
+}
+
+// makeServerStats builds the stats shared by the json rpc 'server.stats'
+// and grpc Stats endpoints. The claimtrie totals are kept up to date by the
+// db on each block, so this is cheap enough for status pages.
+func makeServerStats(DB *db.ReadOnlyDBColumnFamily) (*ServerStatsRes, error) {
+	if DB == nil || DB.LastState == nil {
+		return nil, errors.New("db is nil")
+	}
+	state := DB.LastState
+	stats := DB.GetStats()
+	res := &ServerStatsRes{
+		Height:      state.Height,
+		TxCount:     state.TxCount,
+		Claims:      stats.Claims,
+		Supports:    stats.Supports,
+		ActiveNames: stats.ActiveNames,
+		TotalStaked: stats.TotalStaked,
+	}
+	if state.Tip != nil {
+		res.Tip = state.Tip.String()
+	}
+	return res, nil
+}
+
+// Stats is the json rpc endpoint for 'server.stats'.
+func (t *ServerService) Stats(req *ServerStatsReq, res **ServerStatsRes) error {
+	if t.server == nil {
+		err := errors.New("db is nil")
+		log.Warn(err)
+		return err
+	}
+	stats, err := makeServerStats(t.server.DB)
+	if err != nil {
+		log.Warn(err)
+		return err
+	}
+	*res = stats
+	return nil
+}
+
+type ServerDonationAddressReq struct{}
+
+type ServerDonationAddressRes string
+
+// Donation_address is the json rpc endpoint for 'server.donation_address'.
+func (t *ServerService) Donation_address(req *ServerDonationAddressReq, res **ServerDonationAddressRes) error {
+	addr := ServerDonationAddressRes(t.Args.DonationAddress)
+	*res = &addr
+	return nil
+}
+
+type ServerPaymentAddressReq struct{}
+
+type ServerPaymentAddressRes string
+
+// Payment_address is the json rpc endpoint for 'server.payment_address'.
+func (t *ServerService) Payment_address(req *ServerPaymentAddressReq, res **ServerPaymentAddressRes) error {
+	addr := ServerPaymentAddressRes(t.Args.PaymentAddress)
+	*res = &addr
+	return nil
+}
+
+// ServerAddPeerReq is the features dict of the peer, as
+// returned by its 'server.features'.
+type ServerAddPeerReq struct {
+	Hosts       map[string]ServerFeaturesHost `json:"hosts"`
+	GenesisHash string                        `json:"genesis_hash"`
+	ProtocolMax string                        `json:"protocol_max"`
+}
+
+type ServerAddPeerRes bool
+
+// Add_peer is the json rpc endpoint for 'server.add_peer'. The hosts of
+// the peer, at most maxAddPeerHosts of them, are added to the electrum
+// peer list advertised to clients until they expire. They are not
+// federated with, as their features don't tell us their grpc port.
+func (t *ServerService) Add_peer(req *ServerAddPeerReq, res **ServerAddPeerRes) error {
+	if t.server == nil {
+		return errors.New("federation not available")
+	}
+	added := false
+	if !t.Args.DisableFederation {
+		var err error
+		added, err = t.server.addElectrumPeers(req)
+		if err != nil {
+			log.Warn(err)
+			return err
+		}
+	}
+	result := ServerAddPeerRes(added)
+	*res = &result
+	return nil
+}
+
+// ServerPeersService provides 'server.peers.*' backed by the
+// electrum peer list.
+type ServerPeersService struct {
+	Args   *Args
+	server *Server
+}
+
+type PeersSubscribeReq struct{}
+
+// PeersSubscribeRes is a list of [ip, host, [features]] entries.
+type PeersSubscribeRes [][]interface{}
+
+// Subscribe is the json rpc endpoint for 'server.peers.subscribe'.
+func (t *ServerPeersService) Subscribe(req *PeersSubscribeReq, res **PeersSubscribeRes) error {
+	if t.server == nil {
+		return errors.New("federation not available")
+	}
+	result := make(PeersSubscribeRes, 0)
+	now := time.Now()
+	t.server.ElectrumPeersMut.RLock()
+	for _, peer := range t.server.ElectrumPeers {
+		if peer.expired(now) {
+			continue
+		}
+		features := make([]string, 0, 3)
+		if peer.ProtocolMax != "" {
+			features = append(features, "v"+peer.ProtocolMax)
+		}
+		if peer.SslPort != nil {
+			features = append(features, "s"+strconv.Itoa(*peer.SslPort))
+		}
+		if peer.TcpPort != nil {
+			features = append(features, "t"+strconv.Itoa(*peer.TcpPort))
+		}
+		result = append(result, []interface{}{peer.IP, peer.Host, features})
+	}
+	t.server.ElectrumPeersMut.RUnlock()
+	*res = &result
 	return nil
 }
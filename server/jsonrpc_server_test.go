@@ -0,0 +1,254 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbry.go/v3/extras/stop"
+)
+
+func TestServerVersionReq(t *testing.T) {
+	tests := []struct {
+		raw      string
+		name     string
+		min, max string
+	}{
+		{`"lbry-sdk"`, "lbry-sdk", "", ""},
+		{`["lbry-sdk"]`, "lbry-sdk", "", ""},
+		{`["lbry-sdk", "0.107.0"]`, "lbry-sdk", "0.107.0", "0.107.0"},
+		{`["lbry-sdk", ["0.54.0", "0.110.0"]]`, "lbry-sdk", "0.54.0", "0.110.0"},
+	}
+	for _, tt := range tests {
+		var req ServerVersionReq
+		if err := json.Unmarshal([]byte(tt.raw), &req); err != nil {
+			t.Errorf("%v: unmarshal err: %v", tt.raw, err)
+			continue
+		}
+		if req.ClientName != tt.name || req.ProtocolMin != tt.min || req.ProtocolMax != tt.max {
+			t.Errorf("%v: got %+v", tt.raw, req)
+		}
+	}
+}
+
+func TestNegotiateProtocol(t *testing.T) {
+	tests := []struct {
+		clientMin, clientMax string
+		want                 string
+	}{
+		{"", "", PROTOCOL_MIN},
+		{"0.107.0", "0.107.0", "0.107.0"},
+		{"0.54.0", "1.0", PROTOCOL_MAX},
+		{"0.1", "0.2", ""},
+		{"1.0", "1.1", ""},
+		{"0.x", "0.x", ""},
+	}
+	for _, tt := range tests {
+		ver, err := negotiateProtocol(tt.clientMin, tt.clientMax, PROTOCOL_MIN, PROTOCOL_MAX)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%v-%v: expected error, got %v", tt.clientMin, tt.clientMax, ver)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v-%v: err: %v", tt.clientMin, tt.clientMax, err)
+			continue
+		}
+		if ver.String() != tt.want {
+			t.Errorf("%v-%v: got %v want %v", tt.clientMin, tt.clientMax, ver, tt.want)
+		}
+	}
+}
+
+func TestServerVersionOnce(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	grp := stop.NewDebug()
+	sm := newSessionManager(nil, args, grp, &chaincfg.RegressionNetParams)
+	sm.start()
+	defer sm.stop()
+
+	_, server1 := net.Pipe()
+	sess := sm.addSession(server1)
+	s := &ServerService{args, &chaincfg.RegressionNetParams, nil, sm, sess}
+
+	req := ServerVersionReq{"test-client", "0.107.0", "0.107.0"}
+	var resp *ServerVersionRes
+	if err := s.Version(&req, &resp); err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if resp[1] != "0.107.0" {
+		t.Errorf("bad protocol: %v", resp[1])
+	}
+	if !sm.protocolAtLeast(sess, "0.107.0") || sm.protocolAtLeast(sess, "0.108.0") {
+		t.Errorf("unexpected session protocol: %v", sess.protocol)
+	}
+	if sess.clientName != "test-client" || sess.clientVersion != "" {
+		t.Errorf("unexpected client: %v %v", sess.clientName, sess.clientVersion)
+	}
+	if err := s.Version(&req, &resp); err == nil {
+		t.Errorf("expected error on second server.version")
+	}
+}
+
+func TestSplitClientName(t *testing.T) {
+	tests := []struct {
+		clientName, name, version string
+	}{
+		{"0.113.0", "", "0.113.0"},
+		{"electrum/4.1.5", "electrum", "4.1.5"},
+		{"Electrum 3.0.3", "Electrum", "3.0.3"},
+		{"test-client", "test-client", ""},
+		{"", "", ""},
+	}
+	for _, tt := range tests {
+		name, version := splitClientName(tt.clientName)
+		if name != tt.name || version != tt.version {
+			t.Errorf("%q: got %q %q want %q %q", tt.clientName, name, version, tt.name, tt.version)
+		}
+	}
+}
+
+func TestServerPeersSubscribe(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	tcpPort, sslPort := 50001, 50002
+	s := &Server{
+		Args: args,
+		PeerServers: map[string]*Peer{
+			"1.2.3.4:50051": {Address: "1.2.3.4", Port: "50051"},
+		},
+		ElectrumPeers: map[string]*ElectrumPeer{
+			"hub.example.com": {
+				Host:        "hub.example.com",
+				IP:          "5.6.7.8",
+				TcpPort:     &tcpPort,
+				SslPort:     &sslPort,
+				ProtocolMax: "0.107.0",
+				LastSeen:    time.Now(),
+			},
+			"old.example.com": {
+				Host:     "old.example.com",
+				IP:       "9.9.9.9",
+				TcpPort:  &tcpPort,
+				LastSeen: time.Now().Add(-2 * electrumPeerTimeout),
+			},
+		},
+	}
+	svc := &ServerPeersService{args, s}
+	var resp *PeersSubscribeRes
+	if err := svc.Subscribe(&PeersSubscribeReq{}, &resp); err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	// Federated peers are only advertised once their features are known,
+	// and expired peers aren't advertised.
+	if len(*resp) != 1 {
+		t.Fatalf("expected 1 peer, got %v", *resp)
+	}
+	peer := (*resp)[0]
+	if peer[0] != "5.6.7.8" || peer[1] != "hub.example.com" {
+		t.Errorf("bad peer: %v", peer)
+	}
+	features := peer[2].([]string)
+	if strings.Join(features, ",") != "v0.107.0,s50002,t50001" {
+		t.Errorf("bad features: %v", features)
+	}
+}
+
+func TestServerAddPeer(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	args.DisableFederation = false
+	s := &Server{Args: args, Chain: &chaincfg.RegressionNetParams}
+	svc := &ServerService{Args: args, Chain: s.Chain, server: s}
+	tcpPort := 50001
+	req := &ServerAddPeerReq{
+		Hosts: map[string]ServerFeaturesHost{
+			"1.2.3.4":  {TcpPort: &tcpPort},
+			"10.0.0.1": {TcpPort: &tcpPort},
+			"5.6.7.8":  {},
+		},
+		GenesisHash: chaincfg.RegressionNetParams.GenesisHash.String(),
+		ProtocolMax: "0.107.0",
+	}
+	var resp *ServerAddPeerRes
+	if err := svc.Add_peer(req, &resp); err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if !bool(*resp) {
+		t.Errorf("expected the peer to be added")
+	}
+	// Private hosts and hosts without ports are skipped.
+	if len(s.ElectrumPeers) != 1 || s.ElectrumPeers["1.2.3.4"] == nil {
+		t.Errorf("unexpected peers: %v", s.ElectrumPeers)
+	}
+	// Nothing is dialed, so no federated peer is added.
+	if len(s.PeerServers) != 0 {
+		t.Errorf("unexpected federated peers: %v", s.PeerServers)
+	}
+
+	req.GenesisHash = "00"
+	if err := svc.Add_peer(req, &resp); err == nil {
+		t.Errorf("expected a genesis hash mismatch")
+	}
+
+	hosts := make(map[string]ServerFeaturesHost)
+	for i := 0; i <= maxAddPeerHosts; i++ {
+		hosts[fmt.Sprintf("1.2.3.%d", i)] = ServerFeaturesHost{TcpPort: &tcpPort}
+	}
+	tooMany := &ServerAddPeerReq{
+		Hosts:       hosts,
+		GenesisHash: chaincfg.RegressionNetParams.GenesisHash.String(),
+	}
+	if err := svc.Add_peer(tooMany, &resp); err == nil {
+		t.Errorf("expected too many hosts to be refused")
+	}
+
+	// Expired peers are dropped when peers are added.
+	s.ElectrumPeers["1.2.3.4"].LastSeen = time.Now().Add(-2 * electrumPeerTimeout)
+	delete(req.Hosts, "1.2.3.4")
+	req.Hosts["5.6.7.9"] = ServerFeaturesHost{TcpPort: &tcpPort}
+	req.GenesisHash = tooMany.GenesisHash
+	if err := svc.Add_peer(req, &resp); err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if len(s.ElectrumPeers) != 1 || s.ElectrumPeers["5.6.7.9"] == nil {
+		t.Errorf("unexpected peers: %v", s.ElectrumPeers)
+	}
+
+	args.DisableFederation = true
+	req.GenesisHash = chaincfg.RegressionNetParams.GenesisHash.String()
+	if err := svc.Add_peer(req, &resp); err != nil || bool(*resp) {
+		t.Errorf("expected no peer added without federation: %v %v", *resp, err)
+	}
+}
+
+func TestServerFeatures(t *testing.T) {
+	args := MakeDefaultTestArgs()
+	args.PublicHostnames = []string{"hub.example.com"}
+	args.PublicSSLPort = 50003
+	s := &ServerService{Args: args, Chain: &chaincfg.RegressionNetParams}
+
+	var resp *ServerFeaturesRes
+	if err := s.Features(&ServerFeaturesReq{}, &resp); err != nil {
+		t.Fatalf("handler err: %v", err)
+	}
+	if resp.GenesisHash != chaincfg.RegressionNetParams.GenesisHash.String() {
+		t.Errorf("bad genesis hash: %v", resp.GenesisHash)
+	}
+	host, ok := resp.Hosts["hub.example.com"]
+	if !ok {
+		t.Fatalf("missing host: %+v", resp.Hosts)
+	}
+	if host.TcpPort == nil || *host.TcpPort != DefaultJSONRPCPort {
+		t.Errorf("bad tcp port: %v", host.TcpPort)
+	}
+	if host.SslPort == nil || *host.SslPort != 50003 {
+		t.Errorf("bad ssl port: %v", host.SslPort)
+	}
+	if host.WsPort != nil {
+		t.Errorf("unexpected ws port: %v", *host.WsPort)
+	}
+}
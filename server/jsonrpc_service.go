@@ -11,7 +11,6 @@ import (
 	gorilla_rpc "github.com/gorilla/rpc"
 	gorilla_json "github.com/gorilla/rpc/json"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/net/netutil"
 )
 
 type gorillaRpcCodec struct {
@@ -80,7 +79,7 @@ func (s *Server) StartJsonRPC() error {
 				s.sessionManager.addSession(conn)
 			}
 		}
-		go acceptConnections(netutil.LimitListener(listener, s.sessionManager.sessionsMax))
+		go acceptConnections(listener)
 	}
 
 fail1:
@@ -120,14 +119,29 @@ fail1:
 			log.Errorf("RegisterTCPService: %v\n", err)
 			goto fail2
 		}
+		err = s1.RegisterTCPService(&BlockchainOutpointService{s.DB, s.Chain, nil, nil}, "blockchain_outpoint")
+		if err != nil {
+			log.Errorf("RegisterTCPService: %v\n", err)
+			goto fail2
+		}
+		err = s1.RegisterTCPService(&BlockchainChannelService{s.DB}, "blockchain_channel")
+		if err != nil {
+			log.Errorf("RegisterTCPService: %v\n", err)
+			goto fail2
+		}
 
-		// Register "server.{features,banner,version}" handlers.
-		serverSvc := &ServerService{s.Args}
+		// Register "server.{features,banner,version,ping,...}" handlers.
+		serverSvc := &ServerService{s.Args, s.Chain, s, nil, nil}
 		err = s1.RegisterTCPService(serverSvc, "server")
 		if err != nil {
 			log.Errorf("RegisterTCPService: %v\n", err)
 			goto fail2
 		}
+		err = s1.RegisterTCPService(&ServerPeersService{s.Args, s}, "server_peers")
+		if err != nil {
+			log.Errorf("RegisterTCPService: %v\n", err)
+			goto fail2
+		}
 
 		r := gorilla_mux.NewRouter()
 		r.Handle("/rpc", s1)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"hash"
@@ -22,6 +23,8 @@ import (
 	"github.com/lbryio/herald.go/meta"
 	pb "github.com/lbryio/herald.go/protobuf/go"
 	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/txscript"
+	"github.com/lbryio/lbcutil"
 	"github.com/lbryio/lbry.go/v3/extras/stop"
 	"github.com/olivere/elastic/v7"
 	"github.com/prometheus/client_golang/prometheus"
@@ -50,6 +53,8 @@ type Server struct {
 	PeerSubs         map[string]*Peer
 	PeerSubsMut      sync.RWMutex
 	NumPeerSubs      *int64
+	ElectrumPeers    map[string]*ElectrumPeer
+	ElectrumPeersMut sync.RWMutex
 	ExternalIP       net.IP
 	HeightSubs       map[net.Addr]net.Conn
 	HeightSubsMut    sync.RWMutex
@@ -328,6 +333,7 @@ func MakeHubServer(grp *stop.Group, args *Args) *Server {
 		NumESRefreshes:   0,
 		PeerServers:      make(map[string]*Peer),
 		PeerServersMut:   sync.RWMutex{},
+		ElectrumPeers:    make(map[string]*ElectrumPeer),
 		NumPeerServers:   numPeers,
 		PeerSubs:         make(map[string]*Peer),
 		PeerSubsMut:      sync.RWMutex{},
@@ -339,6 +345,7 @@ func MakeHubServer(grp *stop.Group, args *Args) *Server {
 		Grp:              grp,
 		sessionManager:   newSessionManager(myDB, args, sessionGrp, &chain),
 	}
+	s.sessionManager.server = s
 
 	// Start up our background services
 	if !args.DisableResolve && !args.DisableRocksDBRefresh {
@@ -381,6 +388,14 @@ func MakeHubServer(grp *stop.Group, args *Args) *Server {
 			}
 		}()
 	}
+	if args.AdminRPCPort != 0 {
+		go func() {
+			err := s.StartAdminRPC()
+			if err != nil {
+				log.Println("Admin JSONRPC Server failed!", err)
+			}
+		}()
+	}
 	// Load peers from disk and subscribe to one if there are any
 	if !args.DisableLoadPeers {
 		go func() {
@@ -479,6 +494,37 @@ func (s *Server) Version(ctx context.Context, args *pb.EmptyMessage) (*pb.String
 	return &pb.StringValue{Value: getVersion()}, nil
 }
 
+// Features is a grpc endpoint to get this hub's features, as a json
+// string in the same format as 'server.features'.
+func (s *Server) Features(ctx context.Context, args *pb.EmptyMessage) (*pb.StringValue, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "features"}).Inc()
+	features, err := json.Marshal(makeServerFeatures(s.Args, s.Chain))
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &pb.StringValue{Value: string(features)}, nil
+}
+
+// Stats is the gRPC endpoint for server stats.
+func (s *Server) Stats(ctx context.Context, args *pb.EmptyMessage) (*pb.StatsResponse, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "stats"}).Inc()
+	stats, err := makeServerStats(s.DB)
+	if err != nil {
+		log.Println(err)
+		return nil, err
+	}
+	return &pb.StatsResponse{
+		Height:      stats.Height,
+		Tip:         stats.Tip,
+		TxCount:     stats.TxCount,
+		Claims:      stats.Claims,
+		Supports:    stats.Supports,
+		ActiveNames: stats.ActiveNames,
+		TotalStaked: stats.TotalStaked,
+	}, nil
+}
+
 func (s *Server) Height(ctx context.Context, args *pb.EmptyMessage) (*pb.UInt32Value, error) {
 	metrics.RequestsCount.With(prometheus.Labels{"method": "height"}).Inc()
 	if s.DB != nil {
@@ -560,3 +606,173 @@ func InternalResolve(urls []string, DB *db.ReadOnlyDBColumnFamily) (*pb.Outputs,
 
 	return res, nil
 }
+
+// History is a grpc endpoint returning a page of the confirmed history
+// of a scripthash or address. A non-zero cursor in the response is the
+// value to pass to get the next page.
+func (s *Server) History(ctx context.Context, args *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "history"}).Inc()
+	if s.DB == nil {
+		return nil, errors.New("db is nil")
+	}
+	var hashx []byte
+	if args.Scripthash != "" {
+		scripthash, err := decodeScriptHash(args.Scripthash)
+		if err != nil {
+			return nil, err
+		}
+		hashx = hashX(scripthash)
+	} else if args.Address != "" {
+		address, err := lbcutil.DecodeAddress(args.Address, s.Chain)
+		if err != nil {
+			return nil, err
+		}
+		script, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			return nil, err
+		}
+		hashx = hashXScript(script, s.Chain)
+	} else {
+		return nil, errors.New("scripthash or address required")
+	}
+
+	// Always paginate, zero values mean unbounded.
+	req := &HistoryPageReq{FromHeight: &args.FromHeight, Limit: int(args.Limit)}
+	if args.ToHeight != 0 {
+		req.ToHeight = &args.ToHeight
+	}
+	if args.Cursor != 0 {
+		req.Cursor = &args.Cursor
+	}
+	history, next, err := getHistoryPage(s.DB, hashx, req)
+	if err != nil {
+		return nil, err
+	}
+	res := &pb.HistoryResponse{
+		History: make([]*pb.HistoryItem, 0, len(history)),
+	}
+	for _, tx := range history {
+		res.History = append(res.History, &pb.HistoryItem{TxHash: tx.TxHash, Height: tx.Height})
+	}
+	if next != nil {
+		res.Cursor = *next
+	}
+	return res, nil
+}
+
+// ListClaims is a grpc endpoint returning the claims and supports owned
+// by an address.
+func (s *Server) ListClaims(ctx context.Context, args *pb.ListClaimsRequest) (*pb.Outputs, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "list_claims"}).Inc()
+	if s.DB == nil {
+		return nil, errors.New("db is nil")
+	}
+	address, err := lbcutil.DecodeAddress(args.Address, s.Chain)
+	if err != nil {
+		return nil, err
+	}
+	script, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return nil, err
+	}
+	page := &ClaimtriePageReq{Offset: int(args.Offset), Limit: int(args.Limit)}
+	offset, limit := page.page()
+	return listClaims(s.DB, hashXScript(script, s.Chain), offset, limit)
+}
+
+// ChannelClaims is a grpc endpoint returning a page of the claims in a
+// channel, read from the db rather than elasticsearch.
+func (s *Server) ChannelClaims(ctx context.Context, args *pb.ChannelClaimsRequest) (*pb.Outputs, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "channel_claims"}).Inc()
+	if s.DB == nil {
+		return nil, errors.New("db is nil")
+	}
+	return channelClaims(s.DB, &ChannelClaimsReq{
+		ChannelId: args.ChannelId,
+		OrderBy:   args.OrderBy,
+		Reverse:   args.Reverse,
+		ClaimtriePageReq: ClaimtriePageReq{
+			Offset: int(args.Offset),
+			Limit:  int(args.Limit),
+		},
+	})
+}
+
+// Trending is a grpc endpoint returning the claims with the highest
+// trending scores.
+func (s *Server) Trending(ctx context.Context, args *pb.UInt32Value) (*pb.Outputs, error) {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "trending"}).Inc()
+	if s.DB == nil {
+		return nil, errors.New("db is nil")
+	}
+	return trendingClaims(s.DB, int(args.Value))
+}
+
+// ClaimDiffs is the gRPC endpoint for streaming the claims touched and
+// deleted by each block from from_height through to_height, or following
+// new blocks if to_height is 0. When blocks sent, or the client's
+// block_hash, are disconnected a reorg marker is sent with the height of the
+// first disconnected block, and the stream continues from there. If the
+// client's own block was disconnected the stream ends after the marker.
+func (s *Server) ClaimDiffs(req *pb.ClaimDiffRequest, stream pb.Hub_ClaimDiffsServer) error {
+	metrics.RequestsCount.With(prometheus.Labels{"method": "claim_diffs"}).Inc()
+	if s.DB == nil {
+		return errors.New("db is nil")
+	}
+	if req.ToHeight != 0 && req.ToHeight < req.FromHeight {
+		return errors.New("to_height is before from_height")
+	}
+
+	hashes := make(map[uint32][]byte)
+	if len(req.BlockHash) > 0 && req.FromHeight > 0 {
+		hashes[req.FromHeight-1] = req.BlockHash
+	}
+	next := req.FromHeight
+	for req.ToHeight == 0 || next <= req.ToHeight {
+		height, reorg, err := disconnectedHeight(s.DB, hashes, next)
+		if err != nil {
+			return err
+		}
+		if reorg {
+			if err := stream.Send(&pb.ClaimDiff{Height: height, Reorg: true}); err != nil {
+				return err
+			}
+			if height < req.FromHeight {
+				return nil
+			}
+			for ; next > height; next-- {
+				delete(hashes, next-1)
+			}
+		}
+
+		tip := s.DB.Height
+		if s.DB.LastState != nil {
+			tip = s.DB.LastState.Height
+		}
+		if req.ToHeight != 0 {
+			tip = min(tip, req.ToHeight)
+		}
+		if next > tip {
+			select {
+			case <-stream.Context().Done():
+				return stream.Context().Err()
+			case <-time.After(time.Millisecond * 100):
+			}
+			continue
+		}
+
+		diffs, err := claimDiffs(s.DB, next, min(tip, next+maxClaimDiffBlocks-1), req.Resolve)
+		if err != nil {
+			return err
+		}
+		for _, diff := range diffs {
+			if err := stream.Send(diff); err != nil {
+				return err
+			}
+			hashes[diff.Height] = diff.BlockHash
+			delete(hashes, diff.Height-maxClaimDiffBlocks)
+			next = diff.Height + 1
+		}
+	}
+	return nil
+}
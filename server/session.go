@@ -4,18 +4,22 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net"
 	"net/rpc"
 	"net/rpc/jsonrpc"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 	"unsafe"
 
 	"github.com/lbryio/herald.go/db"
 	"github.com/lbryio/herald.go/internal"
 	"github.com/lbryio/lbcd/chaincfg"
+	"github.com/lbryio/lbcd/chaincfg/chainhash"
+	"github.com/lbryio/lbcd/wire"
 	"github.com/lbryio/lbry.go/v3/extras/stop"
 	log "github.com/sirupsen/logrus"
 )
@@ -33,12 +37,19 @@ type hashXNotification struct {
 	statusStr string
 }
 
+type outpointNotification struct {
+	outpoint wire.OutPoint
+	status   *OutpointStatusResp
+}
+
 type session struct {
 	id   uintptr
 	addr net.Addr
 	conn net.Conn
 	// hashXSubs maps hashX to the original subscription key (address or scripthash)
 	hashXSubs map[[HASHX_LEN]byte]string
+	// outpointSubs are outpoints subscribed via 'blockchain.outpoint.subscribe'
+	outpointSubs map[wire.OutPoint]bool
 	// headersSub indicates header subscription
 	headersSub bool
 	// headersSubRaw indicates the header subscription mode
@@ -46,10 +57,40 @@ type session struct {
 	// client provides the ability to send notifications
 	client    rpc.ClientCodec
 	clientSeq uint64
+	// notifyMut serializes notifications, which are sent from both the
+	// notifier and the manage goroutines
+	notifyMut sync.Mutex
 	// lastRecv records time of last incoming data
 	lastRecv time.Time
 	// lastSend records time of last outgoing data
 	lastSend time.Time
+	// clientName and clientVersion are reported by 'server.version'
+	clientName    string
+	clientVersion string
+	// protocol is the version negotiated by 'server.version'
+	protocol protoVersion
+	// closeAfterResponse disconnects the client after the
+	// current response is written, it is set atomically
+	closeAfterResponse int32
+	// cost accumulates with each request and decays over time
+	cost        float64
+	costUpdated time.Time
+}
+
+const (
+	// sessionRequestCost is the cost charged to a session per request
+	sessionRequestCost = 1.0
+	// sessionCostDecayPerSec is the cost forgiven per second of session time
+	sessionCostDecayPerSec = 0.1
+)
+
+// bumpCost charges the session for a request, first decaying
+// any cost accumulated in the past. The caller must hold
+// sessionsMut.
+func (s *session) bumpCost(now time.Time) {
+	elapsed := now.Sub(s.costUpdated).Seconds()
+	s.cost = max(0, s.cost-elapsed*sessionCostDecayPerSec) + sessionRequestCost
+	s.costUpdated = now
 }
 
 func (s *session) doNotify(notification interface{}) {
@@ -95,11 +136,23 @@ func (s *session) doNotify(notification interface{}) {
 			status = hex.EncodeToString(note.status)
 		}
 		params = []string{orig, status}
+	case outpointNotification:
+		note, _ := notification.(outpointNotification)
+		if !s.outpointSubs[note.outpoint] {
+			return
+		}
+		method = "blockchain.outpoint.subscribe"
+		params = []interface{}{
+			[]interface{}{note.outpoint.Hash.String(), note.outpoint.Index},
+			note.status,
+		}
 	default:
 		log.Warnf("unknown notification type: %v", notification)
 		return
 	}
 	// Send the notification.
+	s.notifyMut.Lock()
+	defer s.notifyMut.Unlock()
 	s.clientSeq += 1
 	req := &rpc.Request{
 		ServiceMethod: method,
@@ -109,8 +162,6 @@ func (s *session) doNotify(notification interface{}) {
 	if err != nil {
 		log.Warnf("error: %v", err)
 	}
-	// Bump last send time.
-	s.lastSend = time.Now()
 }
 
 type sessionMap map[uintptr]*session
@@ -123,6 +174,7 @@ type sessionManager struct {
 	grp            *stop.Group
 	sessionsMax    int
 	sessionTimeout time.Duration
+	manageInterval time.Duration
 	manageTicker   *time.Ticker
 	db             *db.ReadOnlyDBColumnFamily
 	args           *Args
@@ -131,20 +183,42 @@ type sessionManager struct {
 	headerSubs sessionMap
 	// hashXSubs are sessions subscribed via 'blockchain.{address,scripthash}.subscribe'
 	hashXSubs map[[HASHX_LEN]byte]sessionMap
+	// outpointSubs are sessions subscribed via 'blockchain.outpoint.subscribe'
+	outpointSubs map[wire.OutPoint]sessionMap
+	// outpointStatus is the last status sent for each subscribed outpoint
+	outpointStatus map[wire.OutPoint]*OutpointStatusResp
+	// outpointsMut serializes notifyOutpoints, so a status change is only
+	// notified once
+	outpointsMut sync.Mutex
+	// blockCh wakes manage to notify outpoints after a new block, away
+	// from the delivery of header and hashX notifications
+	blockCh chan struct{}
+	// spenders maps the outputs spent by mempool txs to the spending tx,
+	// as of spendersTime, so outpoint lookups share one mempool scan
+	spendersMut  sync.Mutex
+	spenders     map[wire.OutPoint]chainhash.Hash
+	spendersTime time.Time
+	// server provides federation peers for 'server.peers.*', may be nil
+	server *Server
 }
 
 func newSessionManager(db *db.ReadOnlyDBColumnFamily, args *Args, grp *stop.Group, chain *chaincfg.Params) *sessionManager {
+	manageInterval := time.Duration(max(5, args.SessionTimeout/20)) * time.Second
 	return &sessionManager{
 		sessions:       make(sessionMap),
 		grp:            grp,
 		sessionsMax:    args.MaxSessions,
 		sessionTimeout: time.Duration(args.SessionTimeout) * time.Second,
-		manageTicker:   time.NewTicker(time.Duration(max(5, args.SessionTimeout/20)) * time.Second),
+		manageInterval: manageInterval,
+		manageTicker:   time.NewTicker(manageInterval),
 		db:             db,
 		args:           args,
 		chain:          chain,
 		headerSubs:     make(sessionMap),
 		hashXSubs:      make(map[[HASHX_LEN]byte]sessionMap),
+		outpointSubs:   make(map[wire.OutPoint]sessionMap),
+		outpointStatus: make(map[wire.OutPoint]*OutpointStatusResp),
+		blockCh:        make(chan struct{}, 1),
 	}
 }
 
@@ -158,6 +232,8 @@ func (sm *sessionManager) stop() {
 	defer sm.sessionsMut.Unlock()
 	sm.headerSubs = make(sessionMap)
 	sm.hashXSubs = make(map[[HASHX_LEN]byte]sessionMap)
+	sm.outpointSubs = make(map[wire.OutPoint]sessionMap)
+	sm.outpointStatus = make(map[wire.OutPoint]*OutpointStatusResp)
 	for _, sess := range sm.sessions {
 		sess.client.Close()
 		sess.conn.Close()
@@ -175,25 +251,38 @@ func (sm *sessionManager) manage() {
 			}
 		}
 		sm.sessionsMut.Unlock()
-		// Wait for next management clock tick.
+		// Outpoints may be spent by mempool txs at any time.
+		sm.notifyOutpoints()
+		// Wait for next management clock tick, or a new block.
 		select {
 		case <-sm.grp.Ch():
 			sm.grp.Done()
 			return
 		case <-sm.manageTicker.C:
 			continue
+		case <-sm.blockCh:
+			continue
 		}
 	}
 }
 
 func (sm *sessionManager) addSession(conn net.Conn) *session {
 	sm.sessionsMut.Lock()
+	if len(sm.sessions) >= sm.sessionsMax {
+		sm.sessionsMut.Unlock()
+		log.Warnf("session limit %v reached, rejecting %v", sm.sessionsMax, conn.RemoteAddr().String())
+		conn.Close()
+		return nil
+	}
+	now := time.Now()
 	sess := &session{
-		addr:      conn.RemoteAddr(),
-		conn:      conn,
-		hashXSubs: make(map[[11]byte]string),
-		client:    jsonrpc.NewClientCodec(conn),
-		lastRecv:  time.Now(),
+		addr:         conn.RemoteAddr(),
+		conn:         conn,
+		hashXSubs:    make(map[[11]byte]string),
+		outpointSubs: make(map[wire.OutPoint]bool),
+		client:       jsonrpc.NewClientCodec(conn),
+		lastRecv:     now,
+		costUpdated:  now,
 	}
 	sess.id = uintptr(unsafe.Pointer(sess))
 	sm.sessions[sess.id] = sess
@@ -204,13 +293,19 @@ func (sm *sessionManager) addSession(conn net.Conn) *session {
 	// each request and update subscriptions.
 	s1 := rpc.NewServer()
 
-	// Register "server.{features,banner,version}" handlers.
-	serverSvc := &ServerService{sm.args}
+	// Register "server.{features,banner,version,ping,...}" handlers.
+	serverSvc := &ServerService{sm.args, sm.chain, sm.server, sm, sess}
 	err := s1.RegisterName("server", serverSvc)
 	if err != nil {
 		log.Errorf("RegisterName: %v\n", err)
 	}
 
+	// Register "server.peers.*" handlers.
+	err = s1.RegisterName("server.peers", &ServerPeersService{sm.args, sm.server})
+	if err != nil {
+		log.Errorf("RegisterName: %v\n", err)
+	}
+
 	// Register "blockchain.claimtrie.*"" handlers.
 	claimtrieSvc := &ClaimtrieService{sm.db}
 	err = s1.RegisterName("blockchain.claimtrie", claimtrieSvc)
@@ -240,11 +335,22 @@ func (sm *sessionManager) addSession(conn net.Conn) *session {
 		log.Errorf("RegisterName: %v\n", err)
 		goto fail
 	}
+	err = s1.RegisterName("blockchain.outpoint", &BlockchainOutpointService{sm.db, sm.chain, sm, sess})
+	if err != nil {
+		log.Errorf("RegisterName: %v\n", err)
+		goto fail
+	}
+	err = s1.RegisterName("blockchain.channel", &BlockchainChannelService{sm.db})
+	if err != nil {
+		log.Errorf("RegisterName: %v\n", err)
+		goto fail
+	}
 
 	sm.grp.Add(1)
 	go func() {
-		s1.ServeCodec(&sessionServerCodec{jsonrpc.NewServerCodec(newJsonPatchingCodec(conn)), sess})
+		s1.ServeCodec(&sessionServerCodec{jsonrpc.NewServerCodec(newJsonPatchingCodec(conn)), sm, sess})
 		log.Infof("session %v goroutine exit", sess.addr.String())
+		sm.removeSession(sess)
 		sm.grp.Done()
 	}()
 	return sess
@@ -271,11 +377,88 @@ func (sm *sessionManager) removeSessionLocked(sess *session) {
 		}
 		delete(subs, sess.id)
 	}
+	for outpoint := range sess.outpointSubs {
+		sm.outpointUnsubscribeLocked(sess, outpoint)
+	}
 	delete(sm.sessions, sess.id)
 	sess.client.Close()
 	sess.conn.Close()
 }
 
+// markRecv bumps the last receive time of the session and charges it for
+// the request.
+func (sm *sessionManager) markRecv(sess *session) {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	sess.lastRecv = time.Now()
+	sess.bumpCost(sess.lastRecv)
+}
+
+// markSend bumps the last send time of the session.
+func (sm *sessionManager) markSend(sess *session) {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	sess.lastSend = time.Now()
+}
+
+// setClientVersion records the client details reported by 'server.version'
+// and the negotiated protocol. The version may only be set once per session.
+func (sm *sessionManager) setClientVersion(sess *session, clientName, clientVersion string, protocol protoVersion) error {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	if sess.protocol != nil {
+		return errors.New("server.version already sent")
+	}
+	sess.clientName = clientName
+	sess.clientVersion = clientVersion
+	sess.protocol = protocol
+	return nil
+}
+
+// protocolAtLeast reports whether the session negotiated a protocol
+// version of at least ver. Sessions which never sent 'server.version'
+// are treated as using the minimum protocol.
+func (sm *sessionManager) protocolAtLeast(sess *session, ver string) bool {
+	sm.sessionsMut.RLock()
+	protocol := sess.protocol
+	sm.sessionsMut.RUnlock()
+	want, err := parseProtoVersion(ver)
+	if err != nil {
+		return false
+	}
+	if protocol == nil {
+		protocol, err = parseProtoVersion(sm.args.ProtocolMin)
+		if err != nil {
+			return false
+		}
+	}
+	return protocol.compare(want) >= 0
+}
+
+// setMaxSessions changes the session limit. Existing sessions are kept,
+// but new connections are refused until the count drops below the limit.
+func (sm *sessionManager) setMaxSessions(maxSessions int) {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	sm.sessionsMax = maxSessions
+}
+
+// disconnect removes all sessions accepted by the filter and
+// returns the number removed.
+func (sm *sessionManager) disconnect(filter func(sess *session) bool) int {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	count := 0
+	for _, sess := range sm.sessions {
+		if filter(sess) {
+			sm.removeSessionLocked(sess)
+			log.Infof("session %v disconnected", sess.addr.String())
+			count++
+		}
+	}
+	return count
+}
+
 func (sm *sessionManager) headersSubscribe(sess *session, raw bool, subscribe bool) {
 	sm.sessionsMut.Lock()
 	defer sm.sessionsMut.Unlock()
@@ -293,6 +476,20 @@ func (sm *sessionManager) headersSubscribe(sess *session, raw bool, subscribe bo
 func (sm *sessionManager) hashXSubscribe(sess *session, hashX []byte, original string, subscribe bool) {
 	sm.sessionsMut.Lock()
 	defer sm.sessionsMut.Unlock()
+	sm.hashXSubscribeLocked(sess, hashX, original, subscribe)
+}
+
+// hashXSubscribeMany (un)subscribes to several hashXs at once,
+// so that they are all registered before any notification is sent.
+func (sm *sessionManager) hashXSubscribeMany(sess *session, hashXs [][]byte, originals []string, subscribe bool) {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	for i, hashX := range hashXs {
+		sm.hashXSubscribeLocked(sess, hashX, originals[i], subscribe)
+	}
+}
+
+func (sm *sessionManager) hashXSubscribeLocked(sess *session, hashX []byte, original string, subscribe bool) {
 	var key [HASHX_LEN]byte
 	copy(key[:], hashX)
 	subs, ok := sm.hashXSubs[key]
@@ -314,10 +511,127 @@ func (sm *sessionManager) hashXSubscribe(sess *session, hashX []byte, original s
 	delete(sess.hashXSubs, key)
 }
 
+// outpointLastStatus returns the last status sent for the outpoint, if any.
+func (sm *sessionManager) outpointLastStatus(outpoint *wire.OutPoint) *OutpointStatusResp {
+	sm.sessionsMut.RLock()
+	defer sm.sessionsMut.RUnlock()
+	return sm.outpointStatus[*outpoint]
+}
+
+// outpointSubscribe (un)subscribes the session to the outpoint. The status
+// is the one returned to the client, later notifications are sent when it
+// changes. Returns false when unsubscribing from an outpoint which was not
+// subscribed.
+func (sm *sessionManager) outpointSubscribe(sess *session, outpoint *wire.OutPoint, status *OutpointStatusResp, subscribe bool) bool {
+	sm.sessionsMut.Lock()
+	defer sm.sessionsMut.Unlock()
+	if subscribe {
+		subs, ok := sm.outpointSubs[*outpoint]
+		if !ok {
+			subs = make(sessionMap)
+			sm.outpointSubs[*outpoint] = subs
+		}
+		subs[sess.id] = sess
+		sess.outpointSubs[*outpoint] = true
+		sm.outpointStatus[*outpoint] = status
+		return true
+	}
+	if !sess.outpointSubs[*outpoint] {
+		return false
+	}
+	sm.outpointUnsubscribeLocked(sess, *outpoint)
+	return true
+}
+
+func (sm *sessionManager) outpointUnsubscribeLocked(sess *session, outpoint wire.OutPoint) {
+	if subs, ok := sm.outpointSubs[outpoint]; ok {
+		delete(subs, sess.id)
+		if len(subs) == 0 {
+			delete(sm.outpointSubs, outpoint)
+			delete(sm.outpointStatus, outpoint)
+		}
+	}
+	delete(sess.outpointSubs, outpoint)
+}
+
+// mempoolSpenders returns the outputs spent by mempool txs. The mempool is
+// only scanned again once the last scan is older than maxAge.
+func (sm *sessionManager) mempoolSpenders(maxAge time.Duration) (map[wire.OutPoint]chainhash.Hash, error) {
+	sm.spendersMut.Lock()
+	defer sm.spendersMut.Unlock()
+	if sm.spenders != nil && time.Since(sm.spendersTime) < maxAge {
+		return sm.spenders, nil
+	}
+	spenders, err := sm.db.GetMempoolSpenders()
+	if err != nil {
+		return nil, err
+	}
+	sm.spenders = spenders
+	sm.spendersTime = time.Now()
+	return spenders, nil
+}
+
+// notifyOutpoints recomputes the status of all subscribed outpoints
+// and notifies the subscribers of those which changed. The mempool is
+// scanned once for the pass.
+func (sm *sessionManager) notifyOutpoints() {
+	sm.outpointsMut.Lock()
+	defer sm.outpointsMut.Unlock()
+
+	sm.sessionsMut.RLock()
+	prevs := make(map[wire.OutPoint]*OutpointStatusResp, len(sm.outpointStatus))
+	for outpoint, status := range sm.outpointStatus {
+		prevs[outpoint] = status
+	}
+	sm.sessionsMut.RUnlock()
+
+	if len(prevs) == 0 {
+		return
+	}
+	mempoolSpenders, err := sm.mempoolSpenders(0)
+	if err != nil {
+		log.Warn(err)
+		return
+	}
+
+	for outpoint, prev := range prevs {
+		outpoint := outpoint
+		status, err := outpointStatus(sm.db, sm.chain, &outpoint, prev, mempoolSpenders)
+		if err != nil {
+			log.Warn(err)
+			continue
+		}
+		if status.equal(prev) {
+			continue
+		}
+		sm.sessionsMut.Lock()
+		subs, ok := sm.outpointSubs[outpoint]
+		if !ok {
+			sm.sessionsMut.Unlock()
+			continue
+		}
+		sm.outpointStatus[outpoint] = status
+		subsCopy := make(sessionMap, len(subs))
+		for id, sess := range subs {
+			subsCopy[id] = sess
+		}
+		sm.sessionsMut.Unlock()
+
+		note := outpointNotification{outpoint: outpoint, status: status}
+		for _, sess := range subsCopy {
+			sess.doNotify(note)
+			sm.markSend(sess)
+		}
+	}
+}
+
 func (sm *sessionManager) doNotify(notification interface{}) {
 	sm.sessionsMut.RLock()
 	var subsCopy sessionMap
+	newBlock := false
 	switch notification.(type) {
+	case internal.HeightHash, *internal.HeightHash:
+		newBlock = true
 	case headerNotification:
 		note, _ := notification.(headerNotification)
 		subsCopy = sm.headerSubs
@@ -339,14 +653,25 @@ func (sm *sessionManager) doNotify(notification interface{}) {
 	}
 	sm.sessionsMut.RUnlock()
 
+	// Outpoints are notified by manage, so looking them up doesn't hold
+	// up the header and hashX notifications.
+	if newBlock {
+		select {
+		case sm.blockCh <- struct{}{}:
+		default:
+		}
+	}
+
 	// Deliver notification to relevant sessions.
 	for _, sess := range subsCopy {
 		sess.doNotify(notification)
+		sm.markSend(sess)
 	}
 }
 
 type sessionServerCodec struct {
 	rpc.ServerCodec
+	sm   *sessionManager
 	sess *session
 }
 
@@ -388,8 +713,8 @@ func (c *sessionServerCodec) ReadRequestBody(params any) error {
 		return err
 	}
 	log.Infof("from %v receive body: %#v", c.sess.addr.String(), params)
-	// Bump last receive time.
-	c.sess.lastRecv = time.Now()
+	// Bump last receive time and charge for the request.
+	c.sm.markRecv(c.sess)
 	return err
 }
 
@@ -401,7 +726,11 @@ func (c *sessionServerCodec) WriteResponse(resp *rpc.Response, reply any) error
 		return err
 	}
 	// Bump last send time.
-	c.sess.lastSend = time.Now()
+	c.sm.markSend(c.sess)
+	if atomic.LoadInt32(&c.sess.closeAfterResponse) != 0 {
+		log.Infof("closing session %v", c.sess.addr.String())
+		c.sess.conn.Close()
+	}
 	return err
 }
 
@@ -216,12 +216,24 @@ func UDPPing(ip, port string) (*SPVPong, error) {
 	return pong, nil
 }
 
+// pongTip returns the height and tip of the db to report in pongs.
+func (s *Server) pongTip() (int, []byte) {
+	tip := make([]byte, 32)
+	if s.DB == nil || s.DB.LastState == nil {
+		return 0, tip
+	}
+	state := s.DB.LastState
+	if state.Tip != nil {
+		copy(tip, state.Tip[:])
+	}
+	return int(state.Height), tip
+}
+
 // UDPServer is a goroutine that starts an udp server that implements the hubs
 // Ping/Pong protocol to find out about each other without making full TCP
 // connections.
 func (s *Server) UDPServer() error {
 	address := ":" + s.Args.Port
-	tip := make([]byte, 32)
 	addr, err := net.ResolveUDPAddr("udp", address)
 	if err != nil {
 		return err
@@ -243,7 +255,8 @@ func (s *Server) UDPServer() error {
 		}
 
 		sAddr := addr.IP.String()
-		pong := makeSPVPong(defaultFlags|availableFlag, 0, tip, sAddr, s.Args.Country)
+		height, tip := s.pongTip()
+		pong := makeSPVPong(defaultFlags|availableFlag, height, tip, sAddr, s.Args.Country)
 		data := pong.Encode()
 
 		_, err = conn.WriteToUDP(data, addr)